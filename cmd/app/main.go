@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -9,47 +11,185 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/seldomhappy/sqlc-test/config"
 	"github.com/seldomhappy/sqlc-test/internal/api/handler"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	"github.com/seldomhappy/sqlc-test/internal/domain/idempotency"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/cache"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/changefeed"
 	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/outbox"
 	"github.com/seldomhappy/sqlc-test/internal/infrastructure/repository"
 	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+	bookusecase "github.com/seldomhappy/sqlc-test/internal/usecase/book"
 )
 
 func main() {
 	cfg := config.Load()
+	for _, warning := range cfg.Deprecated {
+		log.Println("WARNING:", warning)
+	}
 
-	// Connect to database
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// Initialize infrastructure layer
+	// Only Postgres has transactions, an outbox table and an idempotency
+	// key table; the other drivers keep pending events and idempotency keys
+	// in memory. The live change feed needs Postgres notifications and stays
+	// nil otherwise.
+	var (
+		authorRepo       author.Repository
+		bookRepo         book.Repository
+		ping             func(context.Context) error
+		txm              transaction.Manager = memory.NewTxManager()
+		outboxStore      outbox.Store        = outbox.NewMemoryStore()
+		idempotencyStore idempotency.Store   = memory.NewIdempotencyStore()
+		changeFeed       *changefeed.Hub
+	)
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	defer stopListening()
+	switch cfg.DatabaseDriver {
+	case "memory":
+		log.Println("Using in-memory storage; data is lost on exit")
+		store := memory.NewStore()
+		authorRepo = store.Authors()
+		bookRepo = store.Books()
+		ping = func(context.Context) error { return nil }
+	case "sqlite":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
 
-	conn, err := pgx.Connect(ctx, cfg.DatabaseURL)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		db, err := database.NewSQLite(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		defer db.Close()
+
+		log.Println("Using SQLite storage; the books API is not available and tenants are not isolated")
+		authorRepo = repository.NewSQLiteAuthorRepository(db.GetDB())
+		ping = db.Ping
+	case "mysql":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db, err := database.NewMySQL(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+
+		log.Println("Using MySQL storage; the books API is not available and tenants are not isolated")
+		authorRepo = repository.NewMySQLAuthorRepository(db.GetDB())
+		ping = db.Ping
+	case "postgres":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db, err := database.New(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+
+		if bypass, err := db.BypassesRLS(ctx); err != nil {
+			log.Fatalf("Failed to check database role: %v", err)
+		} else if bypass {
+			log.Println("WARNING: the database role bypasses row-level security; tenants are not isolated")
+		}
+
+		authorRepo = repository.NewWithReplicas(db.GetPool(), db.Replicas())
+		bookRepo = repository.NewBookRepository(db.GetPool())
+		ping = db.Ping
+		expvar.Publish("db_queries", expvar.Func(func() any { return db.QueryStats() }))
+		txm = database.NewTxManager(db.GetPool(), transaction.Options{}, cfg.TxMaxRetries)
+		outboxStore = outbox.NewPostgresStore(db.GetPool())
+		idempotencyStore = repository.NewIdempotencyStore(db.GetPool())
+
+		changeFeed = changefeed.NewHub(cfg.ChangeFeedBuffer)
+		listener := database.NewListener(db.GetPool(), changefeed.Channel, changeFeed.Notify, changeFeed.Reset)
+		go listener.Run(listenCtx)
+	default:
+		log.Fatalf("Unknown DATABASE_DRIVER %q (want postgres, sqlite, mysql or memory)", cfg.DatabaseDriver)
 	}
-	defer conn.Close(context.Background())
 
-	// Initialize infrastructure layer
-	db := database.New(conn)
-	authorRepo := repository.New(db.GetConn())
+	if cfg.AuthorCacheSize > 0 {
+		cached := cache.NewAuthorRepository(authorRepo, cfg.AuthorCacheSize, cfg.AuthorCacheTTL, cfg.AuthorCacheNegativeTTL)
+		expvar.Publish("author_cache", expvar.Func(func() any { return cached.Stats() }))
+		authorRepo = cached
+	}
+
+	sinks, err := newOutboxSinks(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up outbox sinks: %v", err)
+	}
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	relay := outbox.NewRelay(outboxStore, txm, sinks, cfg.OutboxPollInterval, int32(cfg.OutboxBatchSize))
+	go relay.Run(relayCtx)
+	events := outbox.NewAuthorEvents(outboxStore)
 
 	// Initialize use cases
 	listUC := usecase.NewListAuthorsUseCase(authorRepo)
 	getUC := usecase.NewGetAuthorUseCase(authorRepo)
-	createUC := usecase.NewCreateAuthorUseCase(authorRepo)
-	updateUC := usecase.NewUpdateAuthorUseCase(authorRepo)
-	deleteUC := usecase.NewDeleteAuthorUseCase(authorRepo)
+	createUC := usecase.NewCreateAuthorUseCase(authorRepo, txm, events)
+	updateUC := usecase.NewUpdateAuthorUseCase(authorRepo, txm, events)
+	patchUC := usecase.NewPatchAuthorUseCase(authorRepo, txm, events)
+	deleteUC := usecase.NewDeleteAuthorUseCase(authorRepo, txm, events)
+	searchUC := usecase.NewSearchAuthorsUseCase(authorRepo)
+	restoreUC := usecase.NewRestoreAuthorUseCase(authorRepo, txm, events)
+	bulkUC := usecase.NewBulkCreateAuthorsUseCase(authorRepo, txm, events)
+	exportUC := usecase.NewExportAuthorsUseCase(authorRepo)
+	upsertUC := usecase.NewUpsertAuthorUseCase(authorRepo, txm, events)
+	batchGetUC := usecase.NewGetAuthorsByIDsUseCase(authorRepo, cfg.AuthorBatchGetMax)
 
 	// Initialize HTTP handler and routes
-	authorHandler := handler.NewAuthorHandler(listUC, getUC, createUC, updateUC, deleteUC)
+	idempotent := handler.NewIdempotency(idempotencyStore, cfg.IdempotencyKeyTTL)
+	authorHandler := handler.NewAuthorHandler(listUC, getUC, createUC, updateUC, patchUC, deleteUC, searchUC, restoreUC, bulkUC, exportUC, upsertUC, batchGetUC, idempotent)
+
+	for _, id := range cfg.TenantTokens {
+		if err := tenant.Validate(id); err != nil {
+			log.Fatalf("Invalid tenant %q in TENANT_TOKENS: %v", id, err)
+		}
+	}
+	tenants := handler.NewTenantResolver(cfg.TenantHeader, cfg.TenantTokens)
+	admins := handler.NewAdminAuth(cfg.AdminTokens)
+
+	// API routes act for the tenant of the request
+	api := http.NewServeMux()
+	authorHandler.RegisterRoutes(api)
+
+	if bookRepo != nil {
+		bookHandler := handler.NewBookHandler(
+			bookusecase.NewListBooksUseCase(bookRepo),
+			bookusecase.NewListAuthorBooksUseCase(bookRepo, authorRepo),
+			bookusecase.NewGetBookUseCase(bookRepo),
+			bookusecase.NewCreateBookUseCase(bookRepo, authorRepo),
+			bookusecase.NewUpdateBookUseCase(bookRepo, authorRepo),
+			bookusecase.NewDeleteBookUseCase(bookRepo),
+		)
+		bookHandler.RegisterRoutes(api)
+	}
+
+	if changeFeed != nil {
+		streamHandler := handler.NewAuthorStreamHandler(usecase.NewWatchAuthorsUseCase(changeFeed))
+		streamHandler.RegisterRoutes(api)
+	}
 
 	mux := http.NewServeMux()
-	authorHandler.RegisterRoutes(mux)
+	mux.Handle("/", tenants.Middleware(admins.Middleware(api)))
+
+	// Runtime, cache and query statistics
+	mux.Handle("GET /debug/vars", expvar.Handler())
 
 	// Health check endpoint
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
+		if err := ping(r.Context()); err != nil {
+			http.Error(w, `{"status":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{"status":"ok"}`))
 	})
@@ -59,6 +199,11 @@ func main() {
 		Addr:    ":" + strconv.Itoa(cfg.ServerPort),
 		Handler: mux,
 	}
+	if changeFeed != nil {
+		// Shutdown waits for active requests; end the open streams so it
+		// does not wait for clients to disconnect.
+		server.RegisterOnShutdown(changeFeed.Reset)
+	}
 
 	// Graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -79,3 +224,28 @@ func main() {
 	}
 	log.Println("Server stopped gracefully")
 }
+
+// newOutboxSinks builds the sinks named in cfg.OutboxSinks.
+func newOutboxSinks(cfg *config.Config) ([]outbox.Sink, error) {
+	var sinks []outbox.Sink
+	for _, name := range cfg.OutboxSinks {
+		switch name {
+		case "log":
+			sinks = append(sinks, outbox.NewLogSink(nil))
+		case "webhook":
+			if cfg.OutboxWebhookURL == "" {
+				return nil, fmt.Errorf("webhook sink needs OUTBOX_WEBHOOK_URL")
+			}
+			sinks = append(sinks, outbox.NewWebhookSink(cfg.OutboxWebhookURL, &http.Client{Timeout: 10 * time.Second}))
+		case "file":
+			sink, err := outbox.NewFileSink(cfg.OutboxFile)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, fmt.Errorf("unknown sink %q (want log, webhook or file)", name)
+		}
+	}
+	return sinks, nil
+}
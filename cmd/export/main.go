@@ -0,0 +1,77 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/seldomhappy/sqlc-test/config"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/repository"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+)
+
+// export streams every live author of a tenant to a file or stdout as NDJSON
+// or CSV, for offline dumps that should not go through the HTTP server.
+func main() {
+	cfg := config.Load()
+
+	formatFlag := flag.String("format", "ndjson", "output format: ndjson or csv")
+	out := flag.String("o", "-", "output file, or - for stdout")
+	compress := flag.Bool("gzip", false, "gzip-compress the output")
+	tenantID := flag.String("tenant", "", "tenant to export, or * for every tenant")
+	flag.Parse()
+
+	if *tenantID != tenant.All {
+		if err := tenant.Validate(*tenantID); err != nil {
+			log.Fatalf("Invalid -tenant %q: %v", *tenantID, err)
+		}
+	}
+
+	format, err := usecase.ParseExportFormat(*formatFlag)
+	if err != nil {
+		log.Fatalf("Invalid format: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db, err := database.New(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var gz *gzip.Writer
+	if *compress {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	exportUC := usecase.NewExportAuthorsUseCase(repository.New(db.GetPool()))
+	n, err := exportUC.Execute(tenant.With(ctx, *tenantID), w, format)
+	if err != nil {
+		log.Fatalf("Failed to export authors: %v", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			log.Fatalf("Failed to finish gzip stream: %v", err)
+		}
+	}
+	log.Printf("Exported %d author(s)", n)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/config"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/migrate"
+	"github.com/seldomhappy/sqlc-test/migrations"
+)
+
+const usage = `Usage: migrate <command> [args]
+
+Commands:
+  up          apply all pending migrations
+  down [N]    roll back the last N applied migrations (default 1)
+  status      list migrations and whether they are applied
+  force V     record V as the current version without running SQL
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	db, err := database.New(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	m := migrate.New(db.GetPool(), all)
+
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			log.Printf("Applied %06d_%s", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(applied) == 0 {
+			log.Println("No pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if steps, err = strconv.Atoi(args[0]); err != nil {
+				log.Fatalf("Invalid step count %q", args[0])
+			}
+		}
+		rolledBack, err := m.Down(ctx, steps)
+		for _, mig := range rolledBack {
+			log.Printf("Rolled back %06d_%s", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			switch {
+			case s.Unknown:
+				state = "applied " + s.AppliedAt.Format(time.RFC3339) + " (no migration file)"
+			case s.ChecksumMismatch:
+				state = "applied " + s.AppliedAt.Format(time.RFC3339) + " (CHECKSUM MISMATCH)"
+			case s.Applied:
+				state = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%06d_%-40s %s\n", s.Version, s.Name, state)
+		}
+
+	case "force":
+		if len(args) != 1 {
+			log.Fatal("force requires a version")
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid version %q", args[0])
+		}
+		if err := m.Force(ctx, version); err != nil {
+			log.Fatalf("Force failed: %v", err)
+		}
+		log.Printf("Forced schema version to %d", version)
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/config"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/outbox"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/repository"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+)
+
+// purge permanently deletes authors of every tenant that were soft-deleted
+// longer ago than the configured retention, expired idempotency keys and
+// outbox messages published longer ago than the outbox retention. It is meant
+// to be run periodically (e.g. cron).
+func main() {
+	cfg := config.Load()
+
+	retention := flag.Duration("retention", cfg.SoftDeleteRetention, "purge authors soft-deleted longer ago than this")
+	outboxRetention := flag.Duration("outbox-retention", cfg.OutboxRetention, "purge outbox messages published longer ago than this")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	db, err := database.New(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx = tenant.With(ctx, tenant.All)
+	purgeUC := usecase.NewPurgeDeletedAuthorsUseCase(repository.New(db.GetPool()))
+	n, err := purgeUC.Execute(ctx, *retention)
+	if err != nil {
+		log.Fatalf("Failed to purge deleted authors: %v", err)
+	}
+	log.Printf("Purged %d author(s) deleted more than %s ago", n, *retention)
+
+	n, err = repository.NewIdempotencyStore(db.GetPool()).DeleteExpired(ctx, time.Now())
+	if err != nil {
+		log.Fatalf("Failed to purge idempotency keys: %v", err)
+	}
+	log.Printf("Purged %d expired idempotency key(s)", n)
+
+	n, err = outbox.NewPostgresStore(db.GetPool()).PurgePublished(ctx, time.Now().Add(-*outboxRetention))
+	if err != nil {
+		log.Fatalf("Failed to purge published outbox messages: %v", err)
+	}
+	log.Printf("Purged %d outbox message(s) published more than %s ago", n, *outboxRetention)
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds application configuration.
@@ -10,24 +12,146 @@ type Config struct {
 	DatabaseURL string
 	ServerPort  int
 	Environment string
+
+	// DatabaseDriver selects the storage backend: "postgres" (default),
+	// "sqlite", "mysql", or "memory", which needs no database and loses all
+	// data on exit. DatabaseURL is interpreted by the selected driver.
+	// STORAGE_BACKEND, its name before SQLite and MySQL were added, is still
+	// read when DATABASE_DRIVER is unset.
+	DatabaseDriver string
+
+	// Deprecated lists the deprecated environment variables that are set,
+	// each with a note on what replaces it, for the caller to warn about.
+	Deprecated []string
+
+	// DatabaseReplicaURLs lists PostgreSQL read replicas. Author reads
+	// outside a transaction are spread across the healthy ones, which are
+	// re-checked every DBReplicaCheckPeriod.
+	DatabaseReplicaURLs  []string
+	DBReplicaCheckPeriod time.Duration
+
+	// Connection pool settings.
+	DBMaxConns          int32
+	DBMinConns          int32
+	DBMaxConnIdleTime   time.Duration
+	DBMaxConnLifetime   time.Duration
+	DBHealthCheckPeriod time.Duration
+
+	// DBSlowQueryThreshold is how long a Postgres query may take before it
+	// is logged; 0 disables the slow-query log.
+	DBSlowQueryThreshold time.Duration
+
+	// Author cache settings. A size of 0 disables the cache; misses are
+	// cached for AuthorCacheNegativeTTL.
+	AuthorCacheSize        int
+	AuthorCacheTTL         time.Duration
+	AuthorCacheNegativeTTL time.Duration
+
+	// AuthorBatchGetMax is the most IDs GET /authors?ids= and
+	// POST /authors:batchGet accept in one request.
+	AuthorBatchGetMax int
+
+	// TxMaxRetries is how often a transaction failing with a serialization
+	// error is retried.
+	TxMaxRetries int
+
+	// Outbox relay settings. OutboxSinks names where events are published:
+	// "log", "webhook" (POST to OutboxWebhookURL) and/or "file" (append to
+	// OutboxFile).
+	OutboxSinks        []string
+	OutboxWebhookURL   string
+	OutboxFile         string
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+
+	// Tenant resolution. Requests name their tenant in the TenantHeader
+	// header; when TenantTokens (bearer token -> tenant) is set they must
+	// authenticate with one of the tokens instead.
+	TenantHeader string
+	TenantTokens map[string]string
+
+	// AdminTokens are accepted in the X-Admin-Token header for admin-only
+	// options such as listing soft-deleted authors.
+	AdminTokens []string
+
+	// IdempotencyKeyTTL is how long the response to a request sent with an
+	// Idempotency-Key is replayed to retries.
+	IdempotencyKeyTTL time.Duration
+
+	// ChangeFeedBuffer is how many recent author changes GET /authors/stream
+	// keeps for clients resuming with Last-Event-ID.
+	ChangeFeedBuffer int
+
+	// SoftDeleteRetention is how long soft-deleted authors are kept before
+	// the purge command removes them permanently.
+	SoftDeleteRetention time.Duration
+	// OutboxRetention is how long published outbox messages are kept before
+	// the purge command removes them.
+	OutboxRetention time.Duration
 }
 
 // Load loads configuration from environment variables.
 func Load() *Config {
-	port := 8080
-	if p := os.Getenv("SERVER_PORT"); p != "" {
-		if parsed, err := strconv.Atoi(p); err == nil {
-			port = parsed
-		}
+	driver := getEnv("DATABASE_DRIVER", getEnv("STORAGE_BACKEND", "postgres"))
+	var deprecated []string
+	if os.Getenv("STORAGE_BACKEND") != "" {
+		deprecated = append(deprecated, "STORAGE_BACKEND is deprecated and ignored when DATABASE_DRIVER is set; use DATABASE_DRIVER")
 	}
 
 	return &Config{
-		DatabaseURL: getEnv("DATABASE_URL", "user=sqlc dbname=sqlc_db sslmode=disable host=localhost"),
-		ServerPort:  port,
+		DatabaseURL: getEnv("DATABASE_URL", defaultDatabaseURL[driver]),
+		ServerPort:  getEnvInt("SERVER_PORT", 8080),
 		Environment: getEnv("ENVIRONMENT", "development"),
+
+		DatabaseDriver: driver,
+		Deprecated:     deprecated,
+
+		DatabaseReplicaURLs:  getEnvList("DATABASE_REPLICA_URLS", nil),
+		DBReplicaCheckPeriod: getEnvDuration("DB_REPLICA_CHECK_PERIOD", 5*time.Second),
+
+		DBMaxConns:          int32(getEnvInt("DB_MAX_CONNS", 10)),
+		DBMinConns:          int32(getEnvInt("DB_MIN_CONNS", 2)),
+		DBMaxConnIdleTime:   getEnvDuration("DB_MAX_CONN_IDLE_TIME", 5*time.Minute),
+		DBMaxConnLifetime:   getEnvDuration("DB_MAX_CONN_LIFETIME", time.Hour),
+		DBHealthCheckPeriod: getEnvDuration("DB_HEALTH_CHECK_PERIOD", time.Minute),
+
+		DBSlowQueryThreshold: getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+
+		AuthorCacheSize:        getEnvInt("AUTHOR_CACHE_SIZE", 1000),
+		AuthorCacheTTL:         getEnvDuration("AUTHOR_CACHE_TTL", 30*time.Second),
+		AuthorCacheNegativeTTL: getEnvDuration("AUTHOR_CACHE_NEGATIVE_TTL", 5*time.Second),
+
+		AuthorBatchGetMax: getEnvInt("AUTHOR_BATCH_GET_MAX", 100),
+
+		TxMaxRetries: getEnvInt("TX_MAX_RETRIES", 3),
+
+		OutboxSinks:        getEnvList("OUTBOX_SINKS", []string{"log"}),
+		OutboxWebhookURL:   getEnv("OUTBOX_WEBHOOK_URL", ""),
+		OutboxFile:         getEnv("OUTBOX_FILE", "outbox.ndjson"),
+		OutboxPollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+
+		TenantHeader: getEnv("TENANT_HEADER", "X-Tenant-ID"),
+		TenantTokens: getEnvMap("TENANT_TOKENS"),
+
+		AdminTokens: getEnvList("ADMIN_TOKENS", nil),
+
+		IdempotencyKeyTTL: getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+
+		ChangeFeedBuffer: getEnvInt("CHANGE_FEED_BUFFER", 1000),
+
+		SoftDeleteRetention: getEnvDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour),
+		OutboxRetention:     getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
 	}
 }
 
+// defaultDatabaseURL holds the DATABASE_URL default for each driver.
+var defaultDatabaseURL = map[string]string{
+	"postgres": "user=sqlc dbname=sqlc_db sslmode=disable host=localhost",
+	"mysql":    "sqlc@tcp(localhost:3306)/sqlc_db",
+	"sqlite":   "file:sqlc.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite",
+}
+
 // getEnv retrieves an environment variable with a default fallback.
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -35,3 +159,55 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvList retrieves a comma-separated environment variable, skipping empty
+// entries, with a default fallback.
+func getEnvList(key string, defaultValue []string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	if list == nil {
+		return defaultValue
+	}
+	return list
+}
+
+// getEnvMap retrieves a comma-separated list of key=value pairs, skipping
+// malformed entries. It returns nil if the variable is unset.
+func getEnvMap(key string) map[string]string {
+	var m map[string]string
+	for _, item := range getEnvList(key, nil) {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || k == "" {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]string)
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// getEnvInt retrieves an integer environment variable with a default fallback.
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration retrieves a duration environment variable (e.g. "30s") with a default fallback.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
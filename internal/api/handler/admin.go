@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// adminTokenHeader names the header carrying an admin token.
+const adminTokenHeader = "X-Admin-Token"
+
+// adminKey marks the context of a request made with an admin token.
+type adminKey struct{}
+
+// AdminAuth recognizes operators by a token in the X-Admin-Token header.
+// Admin-only options, such as listing soft-deleted authors, are refused for
+// everyone else; without tokens nobody is an admin. An admin still acts for
+// the tenant of the request.
+type AdminAuth struct {
+	tokens []string
+}
+
+// NewAdminAuth creates an AdminAuth accepting any of tokens.
+func NewAdminAuth(tokens []string) *AdminAuth {
+	return &AdminAuth{tokens: tokens}
+}
+
+// Middleware marks requests with a valid admin token (see isAdmin) and
+// rejects those with an unknown one. Requests without the header pass
+// through unmarked.
+func (a *AdminAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(adminTokenHeader)
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !a.valid(token) {
+			writeError(w, apperrors.NewDomainError(apperrors.CodeUnauthorized, "invalid admin token", nil))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withAdmin(r.Context())))
+	})
+}
+
+// valid reports whether token is one of the admin tokens, comparing in
+// constant time.
+func (a *AdminAuth) valid(token string) bool {
+	found := false
+	for _, known := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			found = true
+		}
+	}
+	return found
+}
+
+// withAdmin returns a copy of ctx marked as an admin request.
+func withAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// isAdmin reports whether ctx belongs to a request made with an admin token.
+func isAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
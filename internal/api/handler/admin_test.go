@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuth_Middleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokens     []string
+		token      string
+		wantStatus int
+		wantAdmin  bool
+	}{
+		{"no token", []string{"root"}, "", http.StatusOK, false},
+		{"valid token", []string{"other", "root"}, "root", http.StatusOK, true},
+		{"unknown token", []string{"root"}, "guess", http.StatusUnauthorized, false},
+		{"no tokens configured", nil, "root", http.StatusUnauthorized, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var admin bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				admin = isAdmin(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/authors", nil)
+			if tt.token != "" {
+				req.Header.Set("X-Admin-Token", tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			NewAdminAuth(tt.tokens).Middleware(next).ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus || admin != tt.wantAdmin {
+				t.Errorf("got status %d and admin %v, want %d and %v", w.Code, admin, tt.wantStatus, tt.wantAdmin)
+			}
+		})
+	}
+}
@@ -2,50 +2,164 @@ package handler
 
 import (
 	"encoding/json"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
 	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 )
 
+// mergePatchContentType is the media type of JSON Merge Patch documents
+// (RFC 7396), the only one PATCH /authors/{id} accepts.
+const mergePatchContentType = "application/merge-patch+json"
+
+// listAuthorsResponse is the envelope returned by GET /authors.
+type listAuthorsResponse struct {
+	Authors    []*author.Author `json:"authors"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
 // AuthorHandler handles HTTP requests for author operations.
 type AuthorHandler struct {
-	listUC   *usecase.ListAuthorsUseCase
-	getUC    *usecase.GetAuthorUseCase
-	createUC *usecase.CreateAuthorUseCase
-	updateUC *usecase.UpdateAuthorUseCase
-	deleteUC *usecase.DeleteAuthorUseCase
+	listUC    *usecase.ListAuthorsUseCase
+	getUC     *usecase.GetAuthorUseCase
+	createUC  *usecase.CreateAuthorUseCase
+	updateUC  *usecase.UpdateAuthorUseCase
+	patchUC   *usecase.PatchAuthorUseCase
+	deleteUC  *usecase.DeleteAuthorUseCase
+	searchUC  *usecase.SearchAuthorsUseCase
+	restoreUC *usecase.RestoreAuthorUseCase
+	bulkUC    *usecase.BulkCreateAuthorsUseCase
+	exportUC  *usecase.ExportAuthorsUseCase
+	upsertUC  *usecase.UpsertAuthorUseCase
+
+	batchGetUC *usecase.GetAuthorsByIDsUseCase
+
+	idempotency *Idempotency
 }
 
-// NewAuthorHandler creates a new AuthorHandler.
+// NewAuthorHandler creates a new AuthorHandler. If idempotency is not nil,
+// the mutating routes honor Idempotency-Key headers.
 func NewAuthorHandler(
 	listUC *usecase.ListAuthorsUseCase,
 	getUC *usecase.GetAuthorUseCase,
 	createUC *usecase.CreateAuthorUseCase,
 	updateUC *usecase.UpdateAuthorUseCase,
+	patchUC *usecase.PatchAuthorUseCase,
 	deleteUC *usecase.DeleteAuthorUseCase,
+	searchUC *usecase.SearchAuthorsUseCase,
+	restoreUC *usecase.RestoreAuthorUseCase,
+	bulkUC *usecase.BulkCreateAuthorsUseCase,
+	exportUC *usecase.ExportAuthorsUseCase,
+	upsertUC *usecase.UpsertAuthorUseCase,
+	batchGetUC *usecase.GetAuthorsByIDsUseCase,
+	idempotency *Idempotency,
 ) *AuthorHandler {
 	return &AuthorHandler{
-		listUC:   listUC,
-		getUC:    getUC,
-		createUC: createUC,
-		updateUC: updateUC,
-		deleteUC: deleteUC,
+		listUC:    listUC,
+		getUC:     getUC,
+		createUC:  createUC,
+		updateUC:  updateUC,
+		patchUC:   patchUC,
+		deleteUC:  deleteUC,
+		searchUC:  searchUC,
+		restoreUC: restoreUC,
+		bulkUC:    bulkUC,
+		exportUC:  exportUC,
+		upsertUC:  upsertUC,
+
+		batchGetUC: batchGetUC,
+
+		idempotency: idempotency,
 	}
 }
 
-// ListAuthors handles GET /authors.
+// ListAuthors handles GET /authors?limit=&cursor=&include_deleted=. When any of the q,
+// name_prefix or has_bio filters is present the request is served by
+// SearchAuthors instead, and when ids is present by GetAuthorsByIDs.
+// include_deleted=true is only honored for admin requests (see AdminAuth).
 func (h *AuthorHandler) ListAuthors(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	authors, err := h.listUC.Execute(r.Context())
+	query := r.URL.Query()
+	if query.Has("ids") {
+		h.GetAuthorsByIDs(w, r)
+		return
+	}
+	if query.Has("q") || query.Has("name_prefix") || query.Has("has_bio") {
+		h.SearchAuthors(w, r)
+		return
+	}
+
+	limit, err := parseLimit(r)
 	if err != nil {
-		http.Error(w, `{"error":"failed to list authors"}`, http.StatusInternalServerError)
+		http.Error(w, `{"error":"invalid limit"}`, http.StatusBadRequest)
 		return
 	}
 
-	json.NewEncoder(w).Encode(authors)
+	var includeDeleted bool
+	if v := query.Get("include_deleted"); v != "" {
+		if includeDeleted, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, `{"error":"invalid include_deleted"}`, http.StatusBadRequest)
+			return
+		}
+	}
+	if includeDeleted && !isAdmin(r.Context()) {
+		writeError(w, apperrors.NewDomainError(apperrors.CodeForbidden, "include_deleted requires an admin token", nil))
+		return
+	}
+
+	page, err := h.listUC.Execute(r.Context(), usecase.ListAuthorsParams{
+		Cursor:         query.Get("cursor"),
+		Limit:          limit,
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(listAuthorsResponse{
+		Authors:    nonNil(page.Authors),
+		NextCursor: page.NextCursor,
+	})
+}
+
+// SearchAuthors handles GET /authors?q=&name_prefix=&has_bio=&limit=.
+func (h *AuthorHandler) SearchAuthors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	params := author.SearchAuthorsParams{
+		Query:      query.Get("q"),
+		NamePrefix: query.Get("name_prefix"),
+	}
+	if hb := query.Get("has_bio"); hb != "" {
+		hasBio, err := strconv.ParseBool(hb)
+		if err != nil {
+			http.Error(w, `{"error":"invalid has_bio"}`, http.StatusBadRequest)
+			return
+		}
+		params.HasBio = pgtype.Bool{Bool: hasBio, Valid: true}
+	}
+	limit, err := parseLimit(r)
+	if err != nil {
+		http.Error(w, `{"error":"invalid limit"}`, http.StatusBadRequest)
+		return
+	}
+	params.Limit = int32(limit)
+
+	authors, err := h.searchUC.Execute(r.Context(), params)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(listAuthorsResponse{Authors: nonNil(authors)})
 }
 
 // GetAuthor handles GET /authors/{id}.
@@ -60,10 +174,15 @@ func (h *AuthorHandler) GetAuthor(w http.ResponseWriter, r *http.Request) {
 
 	author, err := h.getUC.Execute(r.Context(), id)
 	if err != nil {
-		http.Error(w, `{"error":"author not found"}`, http.StatusNotFound)
+		writeError(w, err)
+		return
+	}
+	if author == nil {
+		writeError(w, apperrors.NotFoundError)
 		return
 	}
 
+	w.Header().Set("ETag", formatETag(author.Version))
 	json.NewEncoder(w).Encode(author)
 }
 
@@ -79,7 +198,7 @@ func (h *AuthorHandler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
 
 	created, err := h.createUC.Execute(r.Context(), params)
 	if err != nil {
-		http.Error(w, `{"error":"failed to create author"}`, http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -87,7 +206,8 @@ func (h *AuthorHandler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(created)
 }
 
-// UpdateAuthor handles PUT /authors/{id}.
+// UpdateAuthor handles PUT /authors/{id}. The If-Match header must carry the
+// ETag from GET /authors/{id}; a stale ETag yields 412 Precondition Failed.
 func (h *AuthorHandler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -97,22 +217,104 @@ func (h *AuthorHandler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := parseIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
 	var params author.UpdateAuthorParams
 	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
 		http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
 		return
 	}
 	params.ID = id
+	params.Version = version
 
 	if err := h.updateUC.Execute(r.Context(), params); err != nil {
-		http.Error(w, `{"error":"failed to update author"}`, http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// DeleteAuthor handles DELETE /authors/{id}.
+// PatchAuthor handles PATCH /authors/{id} with a JSON Merge Patch (RFC 7396)
+// body such as {"Bio":null}: members left out keep their value and null
+// removes the bio; Name cannot be null. Like PUT, it requires an If-Match
+// header. The patched author is returned with its new ETag.
+func (h *AuthorHandler) PatchAuthor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"invalid author id"}`, http.StatusBadRequest)
+		return
+	}
+
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != mergePatchContentType {
+		w.Header().Set("Accept-Patch", mergePatchContentType)
+		http.Error(w, `{"error":"content type must be `+mergePatchContentType+`"}`, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
+	var params author.PatchAuthorParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
+		return
+	}
+	params.ID = id
+	params.Version = version
+
+	patched, err := h.patchUC.Execute(r.Context(), params)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(patched.Version))
+	json.NewEncoder(w).Encode(patched)
+}
+
+// UpsertAuthor handles PUT /authors/by-external-id/{extId}. It creates the
+// author with that external ID (201 Created) or replaces its name and bio
+// (200 OK), restoring it if it was soft-deleted. Sync jobs use it to mirror
+// an upstream system, so no If-Match is required: the last write wins.
+func (h *AuthorHandler) UpsertAuthor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params author.UpsertAuthorParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
+		return
+	}
+	params.ExternalID = r.PathValue("extId")
+
+	upserted, created, err := h.upsertUC.Execute(r.Context(), params)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(upserted.Version))
+	if created {
+		w.Header().Set("Location", "/authors/"+strconv.FormatInt(upserted.ID, 10))
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(upserted)
+}
+
+// DeleteAuthor handles DELETE /authors/{id}. The author is soft-deleted and
+// can be brought back with POST /authors/{id}:restore. Like PUT, it requires
+// an If-Match header.
 func (h *AuthorHandler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -122,19 +324,89 @@ func (h *AuthorHandler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.deleteUC.Execute(r.Context(), id); err != nil {
-		http.Error(w, `{"error":"failed to delete author"}`, http.StatusInternalServerError)
+	version, err := parseIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
+	if err := h.deleteUC.Execute(r.Context(), author.DeleteAuthorParams{ID: id, Version: version}); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AuthorAction handles POST /authors/{id}:{action} custom methods. ServeMux
+// wildcards must span a whole path segment, so the action is split here.
+func (h *AuthorHandler) AuthorAction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	rawID, action, _ := strings.Cut(r.PathValue("idAction"), ":")
+	switch action {
+	case "restore":
+		r.SetPathValue("id", rawID)
+		h.RestoreAuthor(w, r)
+	default:
+		http.Error(w, `{"error":"unknown action"}`, http.StatusNotFound)
+	}
+}
+
+// RestoreAuthor handles POST /authors/{id}:restore.
+func (h *AuthorHandler) RestoreAuthor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"invalid author id"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.restoreUC.Execute(r.Context(), id); err != nil {
+		writeError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// parseLimit reads the optional limit query parameter; 0 means "use the default".
+func parseLimit(r *http.Request) (int, error) {
+	l := r.URL.Query().Get("limit")
+	if l == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(l)
+}
+
+// nonNil ensures an empty result encodes as [] rather than null.
+func nonNil(authors []*author.Author) []*author.Author {
+	if authors == nil {
+		return []*author.Author{}
+	}
+	return authors
+}
+
+// idempotent wraps a mutating route with h.idempotency, if any.
+func (h *AuthorHandler) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	if h.idempotency == nil {
+		return next
+	}
+	return h.idempotency.Wrap(next)
+}
+
 // RegisterRoutes registers all author routes.
 func (h *AuthorHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /authors", h.ListAuthors)
 	mux.HandleFunc("GET /authors/{id}", h.GetAuthor)
-	mux.HandleFunc("POST /authors", h.CreateAuthor)
-	mux.HandleFunc("PUT /authors/{id}", h.UpdateAuthor)
-	mux.HandleFunc("DELETE /authors/{id}", h.DeleteAuthor)
+	mux.HandleFunc("GET /authors/export", h.ExportAuthors)
+	mux.HandleFunc("POST /authors", h.idempotent(h.CreateAuthor))
+	mux.HandleFunc("POST /authors:bulk", h.idempotent(h.BulkCreateAuthors))
+	mux.HandleFunc("POST /authors:batchGet", h.BatchGetAuthors)
+	mux.HandleFunc("PUT /authors/{id}", h.idempotent(h.UpdateAuthor))
+	mux.HandleFunc("PATCH /authors/{id}", h.idempotent(h.PatchAuthor))
+	mux.HandleFunc("PUT /authors/by-external-id/{extId}", h.idempotent(h.UpsertAuthor))
+	mux.HandleFunc("DELETE /authors/{id}", h.idempotent(h.DeleteAuthor))
+	mux.HandleFunc("POST /authors/{idAction}", h.idempotent(h.AuthorAction))
 }
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+)
+
+// streamHeartbeat is how often an idle stream sends a comment, so proxies
+// do not time it out and dead clients are noticed.
+var streamHeartbeat = 15 * time.Second
+
+// AuthorStreamHandler streams author changes as Server-Sent Events.
+type AuthorStreamHandler struct {
+	watchUC *usecase.WatchAuthorsUseCase
+}
+
+// NewAuthorStreamHandler creates a new AuthorStreamHandler.
+func NewAuthorStreamHandler(watchUC *usecase.WatchAuthorsUseCase) *AuthorStreamHandler {
+	return &AuthorStreamHandler{watchUC: watchUC}
+}
+
+// RegisterRoutes registers the stream route.
+func (h *AuthorStreamHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /authors/stream", h.StreamAuthors)
+}
+
+// StreamAuthors handles GET /authors/stream. Every change is sent as an
+// event named after its type with the change as JSON data. A client
+// reconnecting with Last-Event-ID receives the changes it missed, or a
+// "reset" event if they are no longer available. The stream ends when the
+// client falls too far behind; reconnecting resumes it.
+func (h *AuthorStreamHandler) StreamAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	changes, gap := h.watchUC.Execute(ctx, r.Header.Get("Last-Event-ID"))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	if gap {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case c, ok := <-changes:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(c)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", c.ID, c.Type, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
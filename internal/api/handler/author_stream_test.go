@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/changefeed"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+)
+
+// closedFeed replays changes to every subscriber, then ends the
+// subscription.
+type closedFeed struct {
+	changes []author.Change
+	lastID  string
+}
+
+func (f *closedFeed) Subscribe(ctx context.Context, lastID string) (<-chan author.Change, bool) {
+	f.lastID = lastID
+	ch := make(chan author.Change, len(f.changes))
+	for _, c := range f.changes {
+		ch <- c
+	}
+	close(ch)
+	return ch, false
+}
+
+func TestStreamAuthors(t *testing.T) {
+	// Arrange
+	feed := &closedFeed{changes: []author.Change{
+		{ID: "e-2", Type: author.EventDeleted, AuthorID: 1, Version: 2},
+	}}
+	mux := http.NewServeMux()
+	NewAuthorStreamHandler(usecase.NewWatchAuthorsUseCase(feed)).RegisterRoutes(mux)
+	req := httptest.NewRequest(http.MethodGet, "/authors/stream", nil)
+	req.Header.Set("Last-Event-ID", "e-1")
+	w := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+	if feed.lastID != "e-1" {
+		t.Errorf("expected Last-Event-ID to be passed on, got %q", feed.lastID)
+	}
+	want := "id: e-2\nevent: author.deleted\n" +
+		`data: {"type":"author.deleted","author_id":1,"version":2,"at":"0001-01-01T00:00:00Z"}` + "\n\n"
+	if w.Body.String() != want {
+		t.Errorf("unexpected body:\n%s", w.Body.String())
+	}
+}
+
+func TestStreamAuthors_SendsResetOnGap(t *testing.T) {
+	// Arrange
+	hub := changefeed.NewHub(10)
+	handler := NewAuthorStreamHandler(usecase.NewWatchAuthorsUseCase(hub))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/authors/stream", nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "gone-1")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.StreamAuthors(w, req)
+
+	// Assert
+	if !strings.HasPrefix(w.Body.String(), "event: reset\n") {
+		t.Errorf("expected a reset event, got:\n%s", w.Body.String())
+	}
+	if !w.Flushed {
+		t.Error("expected response to be flushed")
+	}
+}
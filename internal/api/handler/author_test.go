@@ -4,103 +4,165 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/outbox"
 	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 )
 
-type mockRepoForHandler struct {
-	authors []*author.Author
-	err     error
+// newTestRepository returns an in-memory repository seeded with authors.
+func newTestRepository(authors []*author.Author) *memory.AuthorRepository {
+	repo := memory.NewAuthorRepository()
+	repo.SeedAuthors(authors...)
+	return repo
 }
 
-func (m *mockRepoForHandler) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	for _, a := range m.authors {
-		if a.ID == id {
-			return a, nil
-		}
-	}
-	return nil, nil
+// failingRepository fails every read with err. Other methods are not
+// implemented and panic if called.
+type failingRepository struct {
+	author.Repository
+	err error
 }
 
-func (m *mockRepoForHandler) ListAuthors(ctx context.Context) ([]*author.Author, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	return m.authors, nil
+func (f *failingRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
+	return nil, f.err
 }
 
-func (m *mockRepoForHandler) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	a := &author.Author{
-		ID:   1,
-		Name: params.Name,
-		Bio:  params.Bio,
-	}
-	m.authors = append(m.authors, a)
-	return a, nil
+func (f *failingRepository) StreamAuthors(ctx context.Context, fn func(*author.Author) error) error {
+	return f.err
 }
 
-func (m *mockRepoForHandler) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
-	if m.err != nil {
-		return m.err
+func setupHandler() *AuthorHandler {
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:      1,
+			Name:    "Alice",
+			Bio:     pgtype.Text{String: "Author 1", Valid: true},
+			Version: 1,
+		},
+	})
+
+	tx := memory.NewTxManager()
+	events := outbox.NewAuthorEvents(outbox.NewMemoryStore())
+
+	listUC := usecase.NewListAuthorsUseCase(repo)
+	getUC := usecase.NewGetAuthorUseCase(repo)
+	createUC := usecase.NewCreateAuthorUseCase(repo, tx, events)
+	updateUC := usecase.NewUpdateAuthorUseCase(repo, tx, events)
+	patchUC := usecase.NewPatchAuthorUseCase(repo, tx, events)
+	deleteUC := usecase.NewDeleteAuthorUseCase(repo, tx, events)
+	searchUC := usecase.NewSearchAuthorsUseCase(repo)
+	restoreUC := usecase.NewRestoreAuthorUseCase(repo, tx, events)
+	bulkUC := usecase.NewBulkCreateAuthorsUseCase(repo, tx, events)
+	exportUC := usecase.NewExportAuthorsUseCase(repo)
+	upsertUC := usecase.NewUpsertAuthorUseCase(repo, tx, events)
+	batchGetUC := usecase.NewGetAuthorsByIDsUseCase(repo, 3)
+
+	return NewAuthorHandler(listUC, getUC, createUC, updateUC, patchUC, deleteUC, searchUC, restoreUC, bulkUC, exportUC, upsertUC, batchGetUC, nil)
+}
+
+func TestListAuthors_Success(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
 	}
-	for i, a := range m.authors {
-		if a.ID == params.ID {
-			m.authors[i].Name = params.Name
-			m.authors[i].Bio = params.Bio
-			return nil
-		}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %s", ct)
+	}
+	var result listAuthorsResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Authors) != 1 {
+		t.Errorf("expected 1 author, got %d", len(result.Authors))
+	}
+	if result.NextCursor != "" {
+		t.Errorf("expected no next cursor, got '%s'", result.NextCursor)
 	}
-	return nil
 }
 
-func (m *mockRepoForHandler) DeleteAuthor(ctx context.Context, id int64) error {
-	if m.err != nil {
-		return m.err
+func TestListAuthors_InvalidCursor(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors?cursor=bogus!", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
 	}
-	for i, a := range m.authors {
-		if a.ID == id {
-			m.authors = append(m.authors[:i], m.authors[i+1:]...)
-			return nil
-		}
+}
+
+func TestListAuthors_InvalidLimit(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors?limit=abc", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
 	}
-	return nil
 }
 
-func setupHandler() *AuthorHandler {
-	mockRepo := &mockRepoForHandler{
-		authors: []*author.Author{
-			{
-				ID:   1,
-				Name: "Alice",
-				Bio:  pgtype.Text{String: "Author 1", Valid: true},
-			},
-		},
+func TestListAuthors_IncludeDeletedForbidden(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors?include_deleted=true", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d: %s", w.Code, w.Body.String())
 	}
+}
 
-	listUC := usecase.NewListAuthorsUseCase(mockRepo)
-	getUC := usecase.NewGetAuthorUseCase(mockRepo)
-	createUC := usecase.NewCreateAuthorUseCase(mockRepo)
-	updateUC := usecase.NewUpdateAuthorUseCase(mockRepo)
-	deleteUC := usecase.NewDeleteAuthorUseCase(mockRepo)
+func TestListAuthors_IncludeDeletedAdmin(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors?include_deleted=true", nil)
+	req.Header.Set("X-Admin-Token", "root")
+	w := httptest.NewRecorder()
 
-	return NewAuthorHandler(listUC, getUC, createUC, updateUC, deleteUC)
+	// Act
+	NewAdminAuth([]string{"root"}).Middleware(http.HandlerFunc(handler.ListAuthors)).ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
 }
 
-func TestListAuthors_Success(t *testing.T) {
+func TestListAuthors_SearchByNamePrefix(t *testing.T) {
 	// Arrange
 	handler := setupHandler()
-	req := httptest.NewRequest(http.MethodGet, "/authors", nil)
+	req := httptest.NewRequest(http.MethodGet, "/authors?name_prefix=Al&has_bio=true", nil)
 	w := httptest.NewRecorder()
 
 	// Act
@@ -110,8 +172,27 @@ func TestListAuthors_Success(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status 200, got %d", w.Code)
 	}
-	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
-		t.Errorf("expected Content-Type application/json, got %s", ct)
+	var result listAuthorsResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Authors) != 1 || result.Authors[0].Name != "Alice" {
+		t.Errorf("expected [Alice], got %+v", result.Authors)
+	}
+}
+
+func TestListAuthors_SearchInvalidHasBio(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors?has_bio=maybe", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
 	}
 }
 
@@ -136,6 +217,42 @@ func TestGetAuthor_Success(t *testing.T) {
 	if result.Name != "Alice" {
 		t.Errorf("expected name 'Alice', got '%s'", result.Name)
 	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("expected ETag \"1\", got %s", etag)
+	}
+}
+
+func TestGetAuthor_NotFound(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors/999", nil)
+	req.SetPathValue("id", "999")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetAuthor(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestGetAuthor_DatabaseError(t *testing.T) {
+	// Arrange
+	repo := &failingRepository{err: apperrors.DatabaseError(errors.New("connection refused"))}
+	handler := NewAuthorHandler(nil, usecase.NewGetAuthorUseCase(repo), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	req := httptest.NewRequest(http.MethodGet, "/authors/1", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetAuthor(w, req)
+
+	// Assert
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
 }
 
 func TestGetAuthor_InvalidID(t *testing.T) {
@@ -207,6 +324,7 @@ func TestUpdateAuthor_Success(t *testing.T) {
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest(http.MethodPut, "/authors/1", bytes.NewReader(body))
 	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", `"1"`)
 	w := httptest.NewRecorder()
 
 	// Act
@@ -218,11 +336,168 @@ func TestUpdateAuthor_Success(t *testing.T) {
 	}
 }
 
+func TestUpsertAuthor_CreatesThenUpdates(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	upsert := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/authors/by-external-id/crm-42", strings.NewReader(`{"Name":"`+name+`"}`))
+		req.SetPathValue("extId", "crm-42")
+		w := httptest.NewRecorder()
+		handler.UpsertAuthor(w, req)
+		return w
+	}
+
+	// Act
+	created := upsert("Bob")
+	updated := upsert("Robert")
+
+	// Assert
+	if created.Code != http.StatusCreated || created.Header().Get("Location") != "/authors/2" || created.Header().Get("ETag") != `"1"` {
+		t.Errorf("expected 201 with Location /authors/2 and ETag \"1\", got %d, %q, %q",
+			created.Code, created.Header().Get("Location"), created.Header().Get("ETag"))
+	}
+	if updated.Code != http.StatusOK || updated.Header().Get("ETag") != `"2"` {
+		t.Errorf("expected 200 with ETag \"2\", got %d, %q", updated.Code, updated.Header().Get("ETag"))
+	}
+	var got author.Author
+	if err := json.NewDecoder(updated.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.ID != 2 || got.Name != "Robert" || got.ExternalID.String != "crm-42" {
+		t.Errorf("unexpected author %+v", got)
+	}
+}
+
+func TestUpsertAuthor_InvalidPayload(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodPut, "/authors/by-external-id/crm-42", strings.NewReader(`{"Name":`))
+	req.SetPathValue("extId", "crm-42")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.UpsertAuthor(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestUpdateAuthor_MissingIfMatch(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	body, _ := json.Marshal(author.UpdateAuthorParams{Name: "Alice Updated"})
+	req := httptest.NewRequest(http.MethodPut, "/authors/1", bytes.NewReader(body))
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.UpdateAuthor(w, req)
+
+	// Assert
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected status 428, got %d", w.Code)
+	}
+}
+
+func TestUpdateAuthor_StaleIfMatch(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	body, _ := json.Marshal(author.UpdateAuthorParams{Name: "Alice Updated"})
+	req := httptest.NewRequest(http.MethodPut, "/authors/1", bytes.NewReader(body))
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", `"7"`)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.UpdateAuthor(w, req)
+
+	// Assert
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected status 412, got %d", w.Code)
+	}
+}
+
+func TestPatchAuthor_MergePatch(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	patch := func(body, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/authors/1", strings.NewReader(body))
+		req.SetPathValue("id", "1")
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", ifMatch)
+		w := httptest.NewRecorder()
+		handler.PatchAuthor(w, req)
+		return w
+	}
+
+	// Act
+	renamed := patch(`{"Name":"Alicia"}`, `"1"`)
+	cleared := patch(`{"Bio":null}`, renamed.Header().Get("ETag"))
+
+	// Assert
+	if renamed.Code != http.StatusOK || cleared.Code != http.StatusOK {
+		t.Fatalf("expected status 200 twice, got %d: %s and %d: %s", renamed.Code, renamed.Body, cleared.Code, cleared.Body)
+	}
+	var a author.Author
+	json.NewDecoder(renamed.Body).Decode(&a)
+	if a.Name != "Alicia" || a.Bio.String != "Author 1" {
+		t.Errorf("expected the bio to be kept, got %+v", a)
+	}
+	json.NewDecoder(cleared.Body).Decode(&a)
+	if a.Name != "Alicia" || a.Bio.Valid || a.Version != 3 {
+		t.Errorf("expected the bio to be removed, got %+v", a)
+	}
+	if etag := cleared.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("expected ETag \"3\", got %s", etag)
+	}
+}
+
+func TestPatchAuthor_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		ifMatch     string
+		body        string
+		wantStatus  int
+	}{
+		{"wrong content type", "application/json", `"1"`, `{"Name":"Alicia"}`, http.StatusUnsupportedMediaType},
+		{"missing If-Match", "application/merge-patch+json", "", `{"Name":"Alicia"}`, http.StatusPreconditionRequired},
+		{"stale If-Match", "application/merge-patch+json", `"7"`, `{"Name":"Alicia"}`, http.StatusPreconditionFailed},
+		{"not an object", "application/merge-patch+json", `"1"`, `["Alicia"]`, http.StatusBadRequest},
+		{"null name", "application/merge-patch+json", `"1"`, `{"Name":null}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := setupHandler()
+			req := httptest.NewRequest(http.MethodPatch, "/authors/1", strings.NewReader(tt.body))
+			req.SetPathValue("id", "1")
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.PatchAuthor(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body)
+			}
+		})
+	}
+}
+
 func TestDeleteAuthor_Success(t *testing.T) {
 	// Arrange
 	handler := setupHandler()
 	req := httptest.NewRequest(http.MethodDelete, "/authors/1", nil)
 	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", "*")
 	w := httptest.NewRecorder()
 
 	// Act
@@ -233,3 +508,324 @@ func TestDeleteAuthor_Success(t *testing.T) {
 		t.Errorf("expected status 204, got %d", w.Code)
 	}
 }
+
+func TestDeleteAuthor_InvalidIfMatch(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodDelete, "/authors/1", nil)
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", `W/"1"`)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.DeleteAuthor(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestRestoreAuthor_Success(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:        1,
+			Name:      "Alice",
+			DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		},
+	})
+	restoreUC := usecase.NewRestoreAuthorUseCase(repo, memory.NewTxManager(), outbox.NewAuthorEvents(outbox.NewMemoryStore()))
+	handler := NewAuthorHandler(nil, nil, nil, nil, nil, nil, nil, restoreUC, nil, nil, nil, nil, nil)
+	req := httptest.NewRequest(http.MethodPost, "/authors/1:restore", nil)
+	req.SetPathValue("idAction", "1:restore")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.AuthorAction(w, req)
+
+	// Assert
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+	if _, err := repo.GetAuthor(context.Background(), 1); err != nil {
+		t.Errorf("expected author to be restored, got %v", err)
+	}
+}
+
+func TestRestoreAuthor_NotDeleted(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodPost, "/authors/1:restore", nil)
+	req.SetPathValue("idAction", "1:restore")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.AuthorAction(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestAuthorAction_Unknown(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodPost, "/authors/1:explode", nil)
+	req.SetPathValue("idAction", "1:explode")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.AuthorAction(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestRegisterRoutes_RestoreAction(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	setupHandler().RegisterRoutes(mux)
+	req := httptest.NewRequest(http.MethodPost, "/authors/1:restore", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(w, req)
+
+	// Assert
+	// Author 1 is not deleted, so the action is routed but finds nothing to restore.
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "resource not found") {
+		t.Errorf("expected restore handler 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestBulkCreateAuthors_JSONArray(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	body := `[{"name":"Bob"},{"name":"Carol","bio":"Poet"}]`
+	req := httptest.NewRequest(http.MethodPost, "/authors:bulk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BulkCreateAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result bulkCreateAuthorsResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Inserted != 2 || len(result.Errors) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestBulkCreateAuthors_NDJSONBestEffort(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	body := "{\"name\":\"Bob\"}\n{\"name\":\"\"}\n{\"name\":\"Carol\"}\n"
+	req := httptest.NewRequest(http.MethodPost, "/authors:bulk?mode=best_effort", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BulkCreateAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result bulkCreateAuthorsResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Inserted != 2 {
+		t.Errorf("expected 2 inserted, got %d", result.Inserted)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 1 {
+		t.Errorf("expected row 1 to be reported, got %+v", result.Errors)
+	}
+}
+
+func TestBulkCreateAuthors_AllOrNothingRejected(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	body := "{\"name\":\"Bob\"}\n{\"name\":\"\"}\n"
+	req := httptest.NewRequest(http.MethodPost, "/authors:bulk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BulkCreateAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var result bulkCreateAuthorsResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Inserted != 0 || len(result.Errors) != 1 || result.Error != "1 of 2 rows are invalid" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestBulkCreateAuthors_InvalidPayload(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodPost, "/authors:bulk", strings.NewReader("{\"name\":"))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BulkCreateAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestBulkCreateAuthors_InvalidMode(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodPost, "/authors:bulk?mode=maybe", strings.NewReader(`[{"name":"Bob"}]`))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BulkCreateAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestListAuthors_ByIDs(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors?ids=7,1", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result batchGetAuthorsResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Authors) != 1 || result.Authors[0].ID != 1 {
+		t.Errorf("expected author 1, got %+v", result.Authors)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != 7 {
+		t.Errorf("expected 7 to be missing, got %v", result.Missing)
+	}
+}
+
+func TestListAuthors_ByIDsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "not a number", query: "ids=1,x"},
+		{name: "empty", query: "ids="},
+		{name: "too many", query: "ids=1,2,3,4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := setupHandler()
+			req := httptest.NewRequest(http.MethodGet, "/authors?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ListAuthors(w, req)
+
+			// Assert
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestRegisterRoutes_BatchGet(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	setupHandler().RegisterRoutes(mux)
+	req := httptest.NewRequest(http.MethodPost, "/authors:batchGet", strings.NewReader(`{"ids":[1,2,1]}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := strings.TrimSpace(w.Body.String()); !strings.HasPrefix(got, `{"authors":[{"ID":1,`) || !strings.HasSuffix(got, `"missing":[2]}`) {
+		t.Errorf("unexpected body %s", got)
+	}
+}
+
+func TestBatchGetAuthors_InvalidPayload(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodPost, "/authors:batchGet", strings.NewReader(`{"ids":["a"]}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BatchGetAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestBulkCreateAuthors_BodyTooLarge(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	body := `[{"name":"Bob","bio":"` + strings.Repeat("x", maxBulkBody) + `"}]`
+	req := httptest.NewRequest(http.MethodPost, "/authors:bulk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.BulkCreateAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestBulkCreateAuthors_TooManyRows(t *testing.T) {
+	for name, body := range map[string]string{
+		"array":  "[" + strings.Repeat(`{"name":"Bob"},`, maxBulkRows) + `{"name":"Bob"}]`,
+		"ndjson": strings.Repeat("{\"name\":\"Bob\"}\n", maxBulkRows+1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			handler := setupHandler()
+			req := httptest.NewRequest(http.MethodPost, "/authors:bulk", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.BulkCreateAuthors(w, req)
+
+			// Assert
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("expected status 413, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+)
+
+// batchGetAuthorsRequest is the body accepted by POST /authors:batchGet.
+type batchGetAuthorsRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// batchGetAuthorsResponse is the body returned by GET /authors?ids= and
+// POST /authors:batchGet.
+type batchGetAuthorsResponse struct {
+	Authors []*author.Author `json:"authors"`
+	Missing []int64          `json:"missing"`
+}
+
+// GetAuthorsByIDs handles GET /authors?ids=1,2,3. The authors are returned in
+// the order requested, each at most once; IDs that do not exist are listed in
+// missing rather than failing the request.
+func (h *AuthorHandler) GetAuthorsByIDs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var ids []int64
+	for _, v := range r.URL.Query()["ids"] {
+		for _, s := range strings.Split(v, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+			if err != nil {
+				http.Error(w, `{"error":"invalid ids"}`, http.StatusBadRequest)
+				return
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	h.writeAuthorsByIDs(w, r, ids)
+}
+
+// BatchGetAuthors handles POST /authors:batchGet with a body of the form
+// {"ids":[1,2,3]}, for clients whose ID lists do not fit in a URL. It
+// responds like GET /authors?ids=.
+func (h *AuthorHandler) BatchGetAuthors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req batchGetAuthorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
+		return
+	}
+
+	h.writeAuthorsByIDs(w, r, req.IDs)
+}
+
+// writeAuthorsByIDs fetches ids and writes them as a batchGetAuthorsResponse.
+func (h *AuthorHandler) writeAuthorsByIDs(w http.ResponseWriter, r *http.Request, ids []int64) {
+	authors, missing, err := h.batchGetUC.Execute(r.Context(), ids)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if missing == nil {
+		missing = []int64{}
+	}
+	json.NewEncoder(w).Encode(batchGetAuthorsResponse{
+		Authors: nonNil(authors),
+		Missing: missing,
+	})
+}
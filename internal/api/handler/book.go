@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/book"
+)
+
+// BookHandler handles HTTP requests for book operations.
+type BookHandler struct {
+	listUC         *usecase.ListBooksUseCase
+	listByAuthorUC *usecase.ListAuthorBooksUseCase
+	getUC          *usecase.GetBookUseCase
+	createUC       *usecase.CreateBookUseCase
+	updateUC       *usecase.UpdateBookUseCase
+	deleteUC       *usecase.DeleteBookUseCase
+}
+
+// NewBookHandler creates a new BookHandler.
+func NewBookHandler(
+	listUC *usecase.ListBooksUseCase,
+	listByAuthorUC *usecase.ListAuthorBooksUseCase,
+	getUC *usecase.GetBookUseCase,
+	createUC *usecase.CreateBookUseCase,
+	updateUC *usecase.UpdateBookUseCase,
+	deleteUC *usecase.DeleteBookUseCase,
+) *BookHandler {
+	return &BookHandler{
+		listUC:         listUC,
+		listByAuthorUC: listByAuthorUC,
+		getUC:          getUC,
+		createUC:       createUC,
+		updateUC:       updateUC,
+		deleteUC:       deleteUC,
+	}
+}
+
+// ListBooks handles GET /books.
+func (h *BookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	books, err := h.listUC.Execute(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(nonNilBooks(books))
+}
+
+// ListAuthorBooks handles GET /authors/{id}/books.
+func (h *BookHandler) ListAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	authorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"invalid author id"}`, http.StatusBadRequest)
+		return
+	}
+
+	books, err := h.listByAuthorUC.Execute(r.Context(), authorID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(nonNilBooks(books))
+}
+
+// GetBook handles GET /books/{id}.
+func (h *BookHandler) GetBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"invalid book id"}`, http.StatusBadRequest)
+		return
+	}
+
+	b, err := h.getUC.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(b)
+}
+
+// CreateBook handles POST /books.
+func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params book.CreateBookParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.createUC.Execute(r.Context(), params)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateBook handles PUT /books/{id}.
+func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"invalid book id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var params book.UpdateBookParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
+		return
+	}
+	params.ID = id
+
+	if err := h.updateUC.Execute(r.Context(), params); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteBook handles DELETE /books/{id}.
+func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"invalid book id"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.deleteUC.Execute(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// nonNilBooks ensures an empty result encodes as [] rather than null.
+func nonNilBooks(books []*book.Book) []*book.Book {
+	if books == nil {
+		return []*book.Book{}
+	}
+	return books
+}
+
+// RegisterRoutes registers all book routes.
+func (h *BookHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /books", h.ListBooks)
+	mux.HandleFunc("GET /books/{id}", h.GetBook)
+	mux.HandleFunc("POST /books", h.CreateBook)
+	mux.HandleFunc("PUT /books/{id}", h.UpdateBook)
+	mux.HandleFunc("DELETE /books/{id}", h.DeleteBook)
+	mux.HandleFunc("GET /authors/{id}/books", h.ListAuthorBooks)
+}
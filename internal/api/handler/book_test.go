@@ -0,0 +1,377 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/book"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+type mockBookRepoForHandler struct {
+	books []*book.Book
+	err   error
+}
+
+func (m *mockBookRepoForHandler) GetBook(ctx context.Context, id int64) (*book.Book, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, b := range m.books {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, apperrors.NotFoundError
+}
+
+func (m *mockBookRepoForHandler) ListBooks(ctx context.Context) ([]*book.Book, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.books, nil
+}
+
+func (m *mockBookRepoForHandler) ListBooksByAuthor(ctx context.Context, authorID int64) ([]*book.Book, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*book.Book
+	for _, b := range m.books {
+		if b.AuthorID == authorID {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockBookRepoForHandler) CreateBook(ctx context.Context, params book.CreateBookParams) (*book.Book, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	b := &book.Book{
+		ID:          int64(len(m.books) + 1),
+		AuthorID:    params.AuthorID,
+		Title:       params.Title,
+		ISBN:        params.ISBN,
+		PublishedAt: params.PublishedAt,
+	}
+	m.books = append(m.books, b)
+	return b, nil
+}
+
+func (m *mockBookRepoForHandler) UpdateBook(ctx context.Context, params book.UpdateBookParams) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, b := range m.books {
+		if b.ID == params.ID {
+			b.AuthorID = params.AuthorID
+			b.Title = params.Title
+			b.ISBN = params.ISBN
+			b.PublishedAt = params.PublishedAt
+			return nil
+		}
+	}
+	return apperrors.NotFoundError
+}
+
+func (m *mockBookRepoForHandler) DeleteBook(ctx context.Context, id int64) error {
+	if m.err != nil {
+		return m.err
+	}
+	for i, b := range m.books {
+		if b.ID == id {
+			m.books = append(m.books[:i], m.books[i+1:]...)
+			return nil
+		}
+	}
+	return apperrors.NotFoundError
+}
+
+func setupBookHandler() *BookHandler {
+	repo := &mockBookRepoForHandler{
+		books: []*book.Book{
+			{
+				ID:       1,
+				AuthorID: 1,
+				Title:    "First Book",
+				ISBN:     pgtype.Text{String: "978-0000000001", Valid: true},
+			},
+		},
+	}
+
+	authors := newTestRepository([]*author.Author{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol", DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+	})
+
+	return NewBookHandler(
+		usecase.NewListBooksUseCase(repo),
+		usecase.NewListAuthorBooksUseCase(repo, authors),
+		usecase.NewGetBookUseCase(repo),
+		usecase.NewCreateBookUseCase(repo, authors),
+		usecase.NewUpdateBookUseCase(repo, authors),
+		usecase.NewDeleteBookUseCase(repo),
+	)
+}
+
+func TestListBooks_Success(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListBooks(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %s", ct)
+	}
+	var result []book.Book
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result) != 1 {
+		t.Errorf("expected 1 book, got %d", len(result))
+	}
+}
+
+func TestListAuthorBooks_Success(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors/2/books", nil)
+	req.SetPathValue("id", "2")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ListAuthorBooks(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if body := w.Body.String(); body != "[]\n" {
+		t.Errorf("expected empty list, got %s", body)
+	}
+}
+
+func TestListAuthorBooks_AuthorNotFound(t *testing.T) {
+	for _, id := range []string{"3", "99"} {
+		t.Run(id, func(t *testing.T) {
+			// Arrange
+			handler := setupBookHandler()
+			req := httptest.NewRequest(http.MethodGet, "/authors/"+id+"/books", nil)
+			req.SetPathValue("id", id)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ListAuthorBooks(w, req)
+
+			// Assert
+			if w.Code != http.StatusNotFound {
+				t.Errorf("expected status 404, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetBook_Success(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	var result book.Book
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Title != "First Book" {
+		t.Errorf("expected title 'First Book', got '%s'", result.Title)
+	}
+}
+
+func TestGetBook_NotFound(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	req := httptest.NewRequest(http.MethodGet, "/books/999", nil)
+	req.SetPathValue("id", "999")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestGetBook_InvalidID(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	req := httptest.NewRequest(http.MethodGet, "/books/invalid", nil)
+	req.SetPathValue("id", "invalid")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.GetBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestCreateBook_Success(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	body := []byte(`{"AuthorID":1,"Title":"New Book","ISBN":"978-0000000002","PublishedAt":"2020-01-02"}`)
+	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CreateBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var result book.Book
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Title != "New Book" || !result.PublishedAt.Valid {
+		t.Errorf("unexpected book: %+v", result)
+	}
+}
+
+func TestCreateBook_AuthorNotFound(t *testing.T) {
+	for _, id := range []string{"3", "99"} {
+		t.Run(id, func(t *testing.T) {
+			// Arrange
+			handler := setupBookHandler()
+			body := []byte(`{"AuthorID":` + id + `,"Title":"Orphan"}`)
+			req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CreateBook(w, req)
+
+			// Assert
+			if w.Code != http.StatusNotFound {
+				t.Errorf("expected status 404, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateBook_InvalidPayload(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader([]byte("invalid json")))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.CreateBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestUpdateBook_Success(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	body, _ := json.Marshal(book.UpdateBookParams{AuthorID: 1, Title: "Updated"})
+	req := httptest.NewRequest(http.MethodPut, "/books/1", bytes.NewReader(body))
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.UpdateBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+}
+
+func TestUpdateBook_NotFound(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	body, _ := json.Marshal(book.UpdateBookParams{AuthorID: 1, Title: "Ghost"})
+	req := httptest.NewRequest(http.MethodPut, "/books/999", bytes.NewReader(body))
+	req.SetPathValue("id", "999")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.UpdateBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestDeleteBook_Success(t *testing.T) {
+	// Arrange
+	handler := setupBookHandler()
+	req := httptest.NewRequest(http.MethodDelete, "/books/1", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.DeleteBook(w, req)
+
+	// Assert
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+}
+
+func TestRegisterRoutes_AuthorBooks(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	setupHandler().RegisterRoutes(mux)
+	setupBookHandler().RegisterRoutes(mux)
+	req := httptest.NewRequest(http.MethodGet, "/authors/1/books", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	var result []book.Book
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result) != 1 {
+		t.Errorf("expected 1 book, got %d", len(result))
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+)
+
+const (
+	// maxBulkBody is the largest POST /authors:bulk body accepted.
+	maxBulkBody = 10 << 20
+	// maxBulkRows is the most authors one POST /authors:bulk may import.
+	maxBulkRows = 10000
+)
+
+// errTooManyRows is returned by decodeAuthorRows for more than maxBulkRows.
+var errTooManyRows = fmt.Errorf("more than %d authors", maxBulkRows)
+
+// bulkCreateAuthorsResponse is the body returned by POST /authors:bulk.
+type bulkCreateAuthorsResponse struct {
+	Inserted int64                  `json:"inserted"`
+	Errors   []usecase.BulkRowError `json:"errors"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+// BulkCreateAuthors handles POST /authors:bulk?mode=all_or_nothing|best_effort.
+// The body is either a JSON array of authors or a stream of newline-delimited
+// JSON objects of at most maxBulkBody bytes and maxBulkRows rows; larger
+// bodies are rejected with 413. Rows that fail validation are reported by
+// their zero-based index. Only validation errors are per row, even in
+// best_effort mode: the valid rows are inserted in one statement, so a row
+// the database rejects (e.g. a name too long for a MySQL column) fails the
+// whole batch and nothing is inserted.
+func (h *AuthorHandler) BulkCreateAuthors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	mode, err := usecase.ParseBulkMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	rows, err := decodeAuthorRows(http.MaxBytesReader(w, r.Body, maxBulkBody))
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, `{"error":"request body too large"}`, http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, errTooManyRows):
+		http.Error(w, fmt.Sprintf(`{"error":"at most %d authors may be imported at once"}`, maxBulkRows), http.StatusRequestEntityTooLarge)
+		return
+	case err != nil:
+		http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.bulkUC.Execute(r.Context(), rows, mode)
+	if err != nil {
+		if result == nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(statusForError(err))
+		json.NewEncoder(w).Encode(bulkCreateAuthorsResponse{
+			Errors: nonNilRowErrors(result.Errors),
+			Error:  errorMessage(err),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(bulkCreateAuthorsResponse{
+		Inserted: result.Inserted,
+		Errors:   nonNilRowErrors(result.Errors),
+	})
+}
+
+// decodeAuthorRows reads a JSON array or an NDJSON stream of authors from
+// body, one row at a time, and fails with errTooManyRows once it has read
+// more than maxBulkRows.
+func decodeAuthorRows(body io.Reader) ([]author.CreateAuthorParams, error) {
+	br := bufio.NewReader(body)
+	dec := json.NewDecoder(br)
+
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return nil, err
+	}
+
+	array := first == '['
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []author.CreateAuthorParams
+	for !array || dec.More() {
+		var row author.CreateAuthorParams
+		if err := dec.Decode(&row); err != nil {
+			if !array && errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			return nil, err
+		}
+		if len(rows) == maxBulkRows {
+			return nil, errTooManyRows
+		}
+		rows = append(rows, row)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// peekNonSpace skips leading JSON whitespace and returns the next byte
+// without consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+// nonNilRowErrors ensures row errors encode as [] rather than null.
+func nonNilRowErrors(errs []usecase.BulkRowError) []usecase.BulkRowError {
+	if errs == nil {
+		return []usecase.BulkRowError{}
+	}
+	return errs
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// errorResponse is the JSON body of every error response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// statusForCode maps DomainError codes to HTTP status codes.
+var statusForCode = map[string]int{
+	apperrors.CodeNotFound:           http.StatusNotFound,
+	apperrors.CodeValidation:         http.StatusBadRequest,
+	apperrors.CodeConflict:           http.StatusConflict,
+	apperrors.CodePreconditionFailed: http.StatusPreconditionFailed,
+	apperrors.CodeDatabase:           http.StatusInternalServerError,
+	apperrors.CodeUnauthorized:       http.StatusUnauthorized,
+	apperrors.CodeForbidden:          http.StatusForbidden,
+	apperrors.CodeUnprocessable:      http.StatusUnprocessableEntity,
+}
+
+// statusForError returns the HTTP status for err. Errors that are not
+// DomainErrors, or carry an unknown code, are internal server errors.
+func statusForError(err error) int {
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		if status, ok := statusForCode[domainErr.Code]; ok {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// errorMessage returns the client-facing text for err: the DomainError
+// message, so wrapped driver errors never reach the client.
+func errorMessage(err error) string {
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return "internal server error"
+}
+
+// writeError writes err as a JSON error response with errorMessage(err).
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusForError(err))
+	json.NewEncoder(w).Encode(errorResponse{Error: errorMessage(err)})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestStatusForError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", apperrors.NotFoundError, http.StatusNotFound},
+		{"validation", apperrors.ValidationError("bad"), http.StatusBadRequest},
+		{"conflict", apperrors.ConflictError("dup", nil), http.StatusConflict},
+		{"precondition failed", apperrors.PreconditionFailedError, http.StatusPreconditionFailed},
+		{"unprocessable", apperrors.NewDomainError(apperrors.CodeUnprocessable, "reused", nil), http.StatusUnprocessableEntity},
+		{"database", apperrors.DatabaseError(errors.New("conn reset")), http.StatusInternalServerError},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusForError(tt.err); got != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWriteError_HidesWrappedError(t *testing.T) {
+	// Arrange
+	w := httptest.NewRecorder()
+
+	// Act
+	writeError(w, apperrors.DatabaseError(errors.New("password authentication failed")))
+
+	// Assert
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %s", ct)
+	}
+	if strings.Contains(w.Body.String(), "password") {
+		t.Errorf("response leaked wrapped error: %s", w.Body.String())
+	}
+	var body errorResponse
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Error != "database operation failed" {
+		t.Errorf("expected 'database operation failed', got '%s'", body.Error)
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errMissingIfMatch = errors.New("missing If-Match header")
+	errInvalidIfMatch = errors.New("invalid If-Match header")
+)
+
+// formatETag renders a row version as a strong entity tag.
+func formatETag(version int32) string {
+	return `"` + strconv.FormatInt(int64(version), 10) + `"`
+}
+
+// parseIfMatch extracts the expected row version from the If-Match header.
+// "*" matches any current version and is reported as 0.
+func parseIfMatch(r *http.Request) (int32, error) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" {
+		return 0, errMissingIfMatch
+	}
+	if h == "*" {
+		return 0, nil
+	}
+	unquoted, ok := strings.CutPrefix(h, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+	v, err := strconv.ParseInt(unquoted, 10, 32)
+	if err != nil || v <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return int32(v), nil
+}
+
+// writeIfMatchError answers a request whose If-Match header is unusable.
+func writeIfMatchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errMissingIfMatch) {
+		http.Error(w, `{"error":"If-Match header is required"}`, http.StatusPreconditionRequired)
+		return
+	}
+	http.Error(w, `{"error":"invalid If-Match header"}`, http.StatusBadRequest)
+}
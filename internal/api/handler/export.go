@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+)
+
+// contentTypeForFormat maps export formats to response content types.
+var contentTypeForFormat = map[usecase.ExportFormat]string{
+	usecase.ExportFormatNDJSON: "application/x-ndjson",
+	usecase.ExportFormatCSV:    "text/csv; charset=utf-8",
+}
+
+// streamWriter forwards writes to the response, optionally through gzip, and
+// flushes both layers on Flush. written records whether any byte has reached
+// the response, after which errors can no longer be reported as JSON.
+type streamWriter struct {
+	w       io.Writer
+	gz      *gzip.Writer
+	rc      *http.ResponseController
+	written bool
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.written = true
+	return s.w.Write(p)
+}
+
+func (s *streamWriter) Flush() error {
+	if s.gz != nil {
+		if err := s.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	return s.rc.Flush()
+}
+
+// ExportAuthors handles GET /authors/export?format=ndjson|csv. Rows are
+// streamed as they are read from the database; the body is gzip-compressed
+// when the client sends Accept-Encoding: gzip.
+func (h *AuthorHandler) ExportAuthors(w http.ResponseWriter, r *http.Request) {
+	format, err := usecase.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeForFormat[format])
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	sw := &streamWriter{w: w, rc: http.NewResponseController(w)}
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		sw.gz = gzip.NewWriter(w)
+		sw.w = sw.gz
+	}
+
+	_, err = h.exportUC.Execute(r.Context(), sw, format)
+	if err == nil && sw.gz != nil {
+		err = sw.gz.Close()
+	}
+	if err != nil {
+		if !sw.written {
+			w.Header().Del("Content-Encoding")
+			writeError(w, err)
+			return
+		}
+		// The status line is already on the wire; abort the connection so
+		// the client sees a truncated body instead of a silent success.
+		panic(http.ErrAbortHandler)
+	}
+}
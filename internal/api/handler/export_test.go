@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestExportAuthors_CSV(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	setupHandler().RegisterRoutes(mux)
+	req := httptest.NewRequest(http.MethodGet, "/authors/export?format=csv", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+	if want := "id,name,bio,has_bio,version\n1,Alice,Author 1,true,1\n"; w.Body.String() != want {
+		t.Errorf("unexpected body:\n%s", w.Body.String())
+	}
+	if !w.Flushed {
+		t.Error("expected response to be flushed")
+	}
+}
+
+func TestExportAuthors_NDJSONGzip(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors/export", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ExportAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ce := w.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("expected gzip Content-Encoding, got %q", ce)
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("failed to open gzip body: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("failed to read gzip body: %v", err)
+	}
+	if want := `{"ID":1,"Name":"Alice","Bio":"Author 1","ExternalID":null,"DeletedAt":null,"Version":1}` + "\n"; string(body) != want {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+}
+
+func TestExportAuthors_InvalidFormat(t *testing.T) {
+	// Arrange
+	handler := setupHandler()
+	req := httptest.NewRequest(http.MethodGet, "/authors/export?format=xml", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ExportAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestExportAuthors_ErrorBeforeFirstRow(t *testing.T) {
+	// Arrange
+	repo := &failingRepository{err: apperrors.DatabaseError(errors.New("connection refused"))}
+	handler := NewAuthorHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, usecase.NewExportAuthorsUseCase(repo), nil, nil, nil)
+	req := httptest.NewRequest(http.MethodGet, "/authors/export", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ExportAuthors(w, req)
+
+	// Assert
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+	if ce := w.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("expected no Content-Encoding on error, got %q", ce)
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/idempotency"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+const (
+	// idempotencyKeyHeader names the header carrying a client-chosen key.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// replayedHeader marks responses replayed from an earlier request.
+	replayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLen is the longest key accepted.
+	maxIdempotencyKeyLen = 255
+	// maxIdempotentBody is the largest request body that can be sent with
+	// an Idempotency-Key; the body is buffered to fingerprint the request.
+	maxIdempotentBody = 10 << 20
+)
+
+// Idempotency makes mutating requests safe to retry. The first request with
+// a given Idempotency-Key runs and its response is stored; repeats of it
+// within the TTL get the stored response back, marked with an
+// Idempotent-Replayed header, without running again. Reusing a key for a
+// different request (method, URL, If-Match or body) is rejected with 422,
+// and a repeat arriving while the first request is still running gets 409.
+// Requests failing with a 5xx status release their key so they can be
+// retried. Requests without the header are passed through.
+type Idempotency struct {
+	store idempotency.Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewIdempotency creates an Idempotency remembering responses in store for
+// ttl.
+func NewIdempotency(store idempotency.Store, ttl time.Duration) *Idempotency {
+	return &Idempotency{store: store, ttl: ttl, now: time.Now}
+}
+
+// Wrap returns next made idempotent.
+func (i *Idempotency) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			writeError(w, apperrors.ValidationError("Idempotency-Key is too long"))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBody+1))
+		if err != nil {
+			http.Error(w, `{"error":"invalid request payload"}`, http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxIdempotentBody {
+			http.Error(w, `{"error":"request body too large for an Idempotency-Key"}`, http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		now := i.now()
+		fp := fingerprint(r, body)
+		rec, reserved, err := i.store.Reserve(ctx, key, fp, now, now.Add(i.ttl))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if !reserved {
+			switch {
+			case rec.Fingerprint != fp:
+				writeError(w, apperrors.NewDomainError(apperrors.CodeUnprocessable, "Idempotency-Key was used for a different request", nil))
+			case rec.Response == nil:
+				writeError(w, apperrors.ConflictError("a request with this Idempotency-Key is in progress", nil))
+			default:
+				replay(w, rec.Response)
+			}
+			return
+		}
+
+		// The key must be completed or released even if the client goes
+		// away or the handler panics.
+		ctx = context.WithoutCancel(ctx)
+		capture := &responseCapture{ResponseWriter: w}
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := i.store.Release(ctx, key); err != nil {
+				log.Printf("release idempotency key: %v", err)
+			}
+		}()
+
+		next(capture, r)
+
+		if capture.status() >= http.StatusInternalServerError {
+			return
+		}
+		err = i.store.Complete(ctx, key, idempotency.Response{
+			Status: capture.status(),
+			Header: w.Header().Clone(),
+			Body:   capture.body.Bytes(),
+		})
+		if err != nil {
+			log.Printf("complete idempotency key: %v", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// fingerprint identifies a request by its method, URL, If-Match header and
+// body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{r.Method, r.URL.RequestURI(), r.Header.Get("If-Match")} {
+		io.WriteString(h, part)
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay writes a stored response.
+func replay(w http.ResponseWriter, resp *idempotency.Response) {
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(replayedHeader, "true")
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// responseCapture passes a response through while keeping a copy of its
+// status and body.
+type responseCapture struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(code int) {
+	if c.code == 0 {
+		c.code = code
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *responseCapture) Write(p []byte) (int, error) {
+	if c.code == 0 {
+		c.code = http.StatusOK
+	}
+	c.body.Write(p)
+	return c.ResponseWriter.Write(p)
+}
+
+// status returns the status written so far, 200 if none was.
+func (c *responseCapture) status() int {
+	if c.code == 0 {
+		return http.StatusOK
+	}
+	return c.code
+}
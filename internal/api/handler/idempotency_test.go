@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/outbox"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+)
+
+// newIdempotentMux serves the author create and list routes of repo, made
+// idempotent by idem.
+func newIdempotentMux(repo *memory.AuthorRepository, idem *Idempotency) *http.ServeMux {
+	events := outbox.NewAuthorEvents(outbox.NewMemoryStore())
+	createUC := usecase.NewCreateAuthorUseCase(repo, memory.NewTxManager(), events)
+	mux := http.NewServeMux()
+	NewAuthorHandler(usecase.NewListAuthorsUseCase(repo), nil, createUC, nil, nil, nil, nil, nil, nil, nil, nil, nil, idem).RegisterRoutes(mux)
+	return mux
+}
+
+// idempotentRequest builds a request with an Idempotency-Key.
+func idempotentRequest(method, target, key, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(idempotencyKeyHeader, key)
+	return req
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	mux := newIdempotentMux(repo, NewIdempotency(memory.NewIdempotencyStore(), time.Hour))
+	first := httptest.NewRecorder()
+	mux.ServeHTTP(first, idempotentRequest(http.MethodPost, "/authors", "k1", `{"Name":"Ann"}`))
+
+	// Act
+	retry := httptest.NewRecorder()
+	mux.ServeHTTP(retry, idempotentRequest(http.MethodPost, "/authors", "k1", `{"Name":"Ann"}`))
+
+	// Assert
+	if first.Code != http.StatusCreated || retry.Code != http.StatusCreated {
+		t.Fatalf("expected status 201 twice, got %d and %d", first.Code, retry.Code)
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("expected replayed body %s, got %s", first.Body, retry.Body)
+	}
+	if got := retry.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected replayed Content-Type application/json, got %q", got)
+	}
+	if first.Header().Get(replayedHeader) != "" || retry.Header().Get(replayedHeader) != "true" {
+		t.Errorf("expected only the retry to be marked replayed")
+	}
+	if authors, _ := repo.ListAuthors(context.Background()); len(authors) != 1 {
+		t.Errorf("expected 1 author, got %d", len(authors))
+	}
+}
+
+func TestIdempotency_RejectsReusedKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"different body", http.MethodPost, "/authors", `{"Name":"Bob"}`},
+		{"different URL", http.MethodPost, "/authors:bulk", `{"Name":"Ann"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := newTestRepository(nil)
+			mux := newIdempotentMux(repo, NewIdempotency(memory.NewIdempotencyStore(), time.Hour))
+			mux.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/authors", "k1", `{"Name":"Ann"}`))
+			w := httptest.NewRecorder()
+
+			// Act
+			mux.ServeHTTP(w, idempotentRequest(tt.method, tt.target, "k1", tt.body))
+
+			// Assert
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("expected status 422, got %d", w.Code)
+			}
+			if authors, _ := repo.ListAuthors(context.Background()); len(authors) != 1 {
+				t.Errorf("expected 1 author, got %d", len(authors))
+			}
+		})
+	}
+}
+
+func TestIdempotency_ConflictWhileInProgress(t *testing.T) {
+	// Arrange
+	store := memory.NewIdempotencyStore()
+	idem := NewIdempotency(store, time.Hour)
+	req := idempotentRequest(http.MethodPost, "/authors", "k1", `{"Name":"Ann"}`)
+	now := time.Now()
+	if _, ok, _ := store.Reserve(req.Context(), "k1", fingerprint(req, []byte(`{"Name":"Ann"}`)), now, now.Add(time.Hour)); !ok {
+		t.Fatal("expected to reserve the key")
+	}
+	w := httptest.NewRecorder()
+
+	// Act
+	newIdempotentMux(newTestRepository(nil), idem).ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+}
+
+func TestIdempotency_ReleasesKeyOnServerError(t *testing.T) {
+	// Arrange
+	calls := 0
+	next := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+	handler := NewIdempotency(memory.NewIdempotencyStore(), time.Hour).Wrap(next)
+	handler(httptest.NewRecorder(), idempotentRequest(http.MethodDelete, "/authors/1", "k1", ""))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler(w, idempotentRequest(http.MethodDelete, "/authors/1", "k1", ""))
+
+	// Assert
+	if w.Code != http.StatusNoContent || calls != 2 {
+		t.Errorf("expected the retry to run and return 204, got %d after %d call(s)", w.Code, calls)
+	}
+}
+
+func TestIdempotency_ExpiredKeyRunsAgain(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	idem := NewIdempotency(memory.NewIdempotencyStore(), time.Hour)
+	now := time.Now()
+	idem.now = func() time.Time { return now }
+	mux := newIdempotentMux(repo, idem)
+	mux.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/authors", "k1", `{"Name":"Ann"}`))
+	now = now.Add(time.Hour)
+	w := httptest.NewRecorder()
+
+	// Act
+	mux.ServeHTTP(w, idempotentRequest(http.MethodPost, "/authors", "k1", `{"Name":"Bob"}`))
+
+	// Assert
+	if w.Code != http.StatusCreated || w.Header().Get(replayedHeader) != "" {
+		t.Errorf("expected a fresh 201, got %d (replayed %q)", w.Code, w.Header().Get(replayedHeader))
+	}
+	if authors, _ := repo.ListAuthors(context.Background()); len(authors) != 2 {
+		t.Errorf("expected 2 authors, got %d", len(authors))
+	}
+}
+
+func TestIdempotency_KeysAreScopedToTenant(t *testing.T) {
+	// Arrange
+	calls := 0
+	handler := NewIdempotency(memory.NewIdempotencyStore(), time.Hour).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	for _, id := range []string{"acme", "globex"} {
+		req := idempotentRequest(http.MethodDelete, "/authors/1", "k1", "")
+
+		// Act
+		handler(httptest.NewRecorder(), req.WithContext(tenant.With(req.Context(), id)))
+	}
+
+	// Assert
+	if calls != 2 {
+		t.Errorf("expected each tenant's request to run, got %d call(s)", calls)
+	}
+}
+
+func TestIdempotency_WithoutKeyPassesThrough(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	mux := newIdempotentMux(repo, NewIdempotency(memory.NewIdempotencyStore(), time.Hour))
+
+	// Act
+	for range 2 {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/authors", strings.NewReader(`{"Name":"Ann"}`)))
+	}
+
+	// Assert
+	if authors, _ := repo.ListAuthors(context.Background()); len(authors) != 2 {
+		t.Errorf("expected 2 authors, got %d", len(authors))
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// TenantResolver determines which tenant a request acts for. With tokens
+// configured, a request must carry "Authorization: Bearer <token>" and acts
+// for that token's tenant; a tenant header, if also sent, must agree.
+// Without tokens the tenant header is trusted as is, which is only safe
+// behind a gateway that sets it.
+type TenantResolver struct {
+	header string
+	tokens map[string]string // bearer token -> tenant
+}
+
+// NewTenantResolver creates a TenantResolver reading the tenant from header
+// and, if tokens is not empty, authenticating requests by bearer token.
+func NewTenantResolver(header string, tokens map[string]string) *TenantResolver {
+	return &TenantResolver{header: header, tokens: tokens}
+}
+
+// Middleware scopes every request to its tenant (see tenant.With) and
+// rejects requests without a valid one.
+func (tr *TenantResolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := tr.resolve(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.With(r.Context(), id)))
+	})
+}
+
+// resolve returns the tenant r acts for.
+func (tr *TenantResolver) resolve(r *http.Request) (string, error) {
+	claimed := r.Header.Get(tr.header)
+	if len(tr.tokens) == 0 {
+		if claimed == "" {
+			return "", apperrors.ValidationError("missing " + tr.header + " header")
+		}
+		return claimed, tenant.Validate(claimed)
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return "", apperrors.NewDomainError(apperrors.CodeUnauthorized, "missing bearer token", nil)
+	}
+	id, ok := tr.lookup(token)
+	if !ok {
+		return "", apperrors.NewDomainError(apperrors.CodeUnauthorized, "invalid bearer token", nil)
+	}
+	if claimed != "" && claimed != id {
+		return "", apperrors.NewDomainError(apperrors.CodeForbidden, "token is not valid for this tenant", nil)
+	}
+	return id, nil
+}
+
+// lookup returns the tenant of token, comparing in constant time.
+func (tr *TenantResolver) lookup(token string) (string, bool) {
+	var id string
+	found := false
+	for known, t := range tr.tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			id, found = t, true
+		}
+	}
+	return id, found
+}
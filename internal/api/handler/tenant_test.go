@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/migrate"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/outbox"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/repository"
+	usecase "github.com/seldomhappy/sqlc-test/internal/usecase/author"
+	bookusecase "github.com/seldomhappy/sqlc-test/internal/usecase/book"
+	"github.com/seldomhappy/sqlc-test/migrations"
+)
+
+func TestTenantResolver_Middleware(t *testing.T) {
+	tokens := map[string]string{"s3cret": "acme"}
+	tests := []struct {
+		name       string
+		tokens     map[string]string
+		header     string
+		auth       string
+		wantStatus int
+		wantTenant string
+	}{
+		{"header", nil, "acme", "", http.StatusOK, "acme"},
+		{"missing header", nil, "", "", http.StatusBadRequest, ""},
+		{"malformed header", nil, "a/b", "", http.StatusBadRequest, ""},
+		{"all tenants", nil, tenant.All, "", http.StatusBadRequest, ""},
+		{"token", tokens, "", "Bearer s3cret", http.StatusOK, "acme"},
+		{"token and matching header", tokens, "acme", "Bearer s3cret", http.StatusOK, "acme"},
+		{"token for another tenant", tokens, "globex", "Bearer s3cret", http.StatusForbidden, ""},
+		{"header without token", tokens, "acme", "", http.StatusUnauthorized, ""},
+		{"unknown token", tokens, "", "Bearer guess", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = tenant.FromContext(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/authors", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			NewTenantResolver("X-Tenant-ID", tt.tokens).Middleware(next).ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus || got != tt.wantTenant {
+				t.Errorf("got status %d and tenant %q, want %d and %q", w.Code, got, tt.wantStatus, tt.wantTenant)
+			}
+		})
+	}
+}
+
+// newTenantAPI serves the author and book routes backed by the given
+// repositories behind a header-based TenantResolver, as cmd/app does.
+func newTenantAPI(authors author.Repository, books book.Repository, tx transaction.Manager) http.Handler {
+	events := outbox.NewAuthorEvents(outbox.NewMemoryStore())
+	api := http.NewServeMux()
+	NewAuthorHandler(
+		usecase.NewListAuthorsUseCase(authors),
+		usecase.NewGetAuthorUseCase(authors),
+		usecase.NewCreateAuthorUseCase(authors, tx, events),
+		usecase.NewUpdateAuthorUseCase(authors, tx, events),
+		usecase.NewPatchAuthorUseCase(authors, tx, events),
+		usecase.NewDeleteAuthorUseCase(authors, tx, events),
+		usecase.NewSearchAuthorsUseCase(authors),
+		usecase.NewRestoreAuthorUseCase(authors, tx, events),
+		usecase.NewBulkCreateAuthorsUseCase(authors, tx, events),
+		usecase.NewExportAuthorsUseCase(authors),
+		usecase.NewUpsertAuthorUseCase(authors, tx, events),
+		usecase.NewGetAuthorsByIDsUseCase(authors, 0),
+		nil,
+	).RegisterRoutes(api)
+	NewBookHandler(
+		bookusecase.NewListBooksUseCase(books),
+		bookusecase.NewListAuthorBooksUseCase(books, authors),
+		bookusecase.NewGetBookUseCase(books),
+		bookusecase.NewCreateBookUseCase(books, authors),
+		bookusecase.NewUpdateBookUseCase(books, authors),
+		bookusecase.NewDeleteBookUseCase(books),
+	).RegisterRoutes(api)
+	return NewTenantResolver("X-Tenant-ID", nil).Middleware(NewAdminAuth([]string{"root"}).Middleware(api))
+}
+
+func TestTenantIsolation(t *testing.T) {
+	store := memory.NewStore()
+	testTenantIsolation(t, newTenantAPI(store.Authors(), store.Books(), memory.NewTxManager()))
+}
+
+// TestTenantIsolation_Postgres proves isolation is enforced by row-level
+// security. It is skipped unless TEST_DATABASE_URL points at a disposable
+// database whose user may create roles: the authors table is truncated and
+// the test connects as a role subject to row-level security.
+func TestTenantIsolation_Postgres(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(admin.Close)
+
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrate.New(admin, all).Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := admin.Exec(ctx, "TRUNCATE authors RESTART IDENTITY CASCADE"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	const role = "sqlc_tenant_test"
+	_, err = admin.Exec(ctx, `DO $$ BEGIN
+  IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = '`+role+`') THEN CREATE ROLE `+role+` NOLOGIN; END IF;
+END $$;
+GRANT SELECT, INSERT, UPDATE, DELETE ON authors, books, outbox TO `+role+`;
+GRANT USAGE ON ALL SEQUENCES IN SCHEMA public TO `+role+`;
+GRANT `+role+` TO CURRENT_USER`)
+	if err != nil {
+		t.Skipf("cannot set up role %s: %v", role, err)
+	}
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, "SET ROLE "+role)
+		return err
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect as %s: %v", role, err)
+	}
+	t.Cleanup(pool.Close)
+
+	tx := database.NewTxManager(pool, transaction.Options{}, 0)
+	testTenantIsolation(t, newTenantAPI(repository.New(pool), repository.NewBookRepository(pool), tx))
+}
+
+// testTenantIsolation has tenant acme create an author with a book, then
+// checks that tenant globex can neither see nor change them through any
+// /authors route.
+func testTenantIsolation(t *testing.T, api http.Handler) {
+	t.Helper()
+	do := func(tenantID, method, target, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("X-Tenant-ID", tenantID)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("acme", http.MethodPost, "/authors", `{"Name":"Ann","Bio":"secret gardening notes"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create author: status %d: %s", w.Code, w.Body)
+	}
+	var created author.Author
+	json.NewDecoder(w.Body).Decode(&created)
+	id := fmt.Sprint(created.ID)
+	w = do("acme", http.MethodPost, "/books", `{"AuthorID":`+id+`,"Title":"Acme Almanac","ISBN":"978-1"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create book: status %d: %s", w.Code, w.Body)
+	}
+	if w := do("acme", http.MethodPut, "/authors/by-external-id/ext-1", `{"Name":"Ann Upstream"}`); w.Code != http.StatusCreated {
+		t.Fatalf("upsert author: status %d: %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		method, target, body string
+		header               []string
+		wantStatus           int
+	}{
+		{http.MethodGet, "/authors", "", nil, http.StatusOK},
+		{http.MethodGet, "/authors?include_deleted=true", "", []string{"X-Admin-Token", "root"}, http.StatusOK},
+		{http.MethodGet, "/authors?q=gardening", "", nil, http.StatusOK},
+		{http.MethodGet, "/authors?ids=" + id, "", nil, http.StatusOK},
+		{http.MethodPost, "/authors:batchGet", `{"ids":[` + id + `]}`, nil, http.StatusOK},
+		{http.MethodGet, "/authors/export", "", nil, http.StatusOK},
+		{http.MethodGet, "/authors/" + id, "", nil, http.StatusNotFound},
+		{http.MethodGet, "/authors/" + id + "/books", "", nil, http.StatusNotFound},
+		{http.MethodPut, "/authors/" + id, `{"Name":"Mallory"}`, []string{"If-Match", "*"}, http.StatusNotFound},
+		{http.MethodPatch, "/authors/" + id, `{"Name":"Mallory"}`, []string{"If-Match", "*", "Content-Type", "application/merge-patch+json"}, http.StatusNotFound},
+		{http.MethodDelete, "/authors/" + id, "", []string{"If-Match", "*"}, http.StatusNotFound},
+		{http.MethodPost, "/authors/" + id + ":restore", "", nil, http.StatusNotFound},
+		{http.MethodPost, "/books", `{"AuthorID":` + id + `,"Title":"Hijack","ISBN":"978-2"}`, nil, http.StatusNotFound},
+		{http.MethodPut, "/authors/by-external-id/ext-1", `{"Name":"Mallory"}`, nil, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			w := do("globex", tt.method, tt.target, tt.body, tt.header...)
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body)
+			}
+			if body := w.Body.String(); strings.Contains(body, "Ann") || strings.Contains(body, "Acme Almanac") {
+				t.Errorf("response leaks acme's data: %s", body)
+			}
+		})
+	}
+
+	// acme's rows are untouched, and globex's own writes stay with globex.
+	if w := do("globex", http.MethodPost, "/authors:bulk", `[{"Name":"Gus"}]`); w.Code != http.StatusOK {
+		t.Fatalf("bulk create: status %d: %s", w.Code, w.Body)
+	}
+	w = do("acme", http.MethodGet, "/authors/"+id, "")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"1"` {
+		t.Errorf("expected acme's author unchanged, got status %d, ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+	if body := do("acme", http.MethodGet, "/authors", "").Body.String(); strings.Contains(body, "Gus") || strings.Contains(body, "Mallory") {
+		t.Errorf("acme sees globex's authors: %s", body)
+	} else if !strings.Contains(body, "Ann Upstream") {
+		t.Errorf("expected acme's upserted author to be unchanged, got %s", body)
+	}
+	if body := do("acme", http.MethodGet, "/authors/"+id+"/books", "").Body.String(); !strings.Contains(body, "Acme Almanac") {
+		t.Errorf("expected acme to see its book, got %s", body)
+	}
+}
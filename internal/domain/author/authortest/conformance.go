@@ -0,0 +1,581 @@
+// Package authortest provides a conformance suite for author.Repository
+// implementations. Every backend should pass it so that the use cases behave
+// the same no matter which repository they are wired to:
+//
+//	func TestConformance(t *testing.T) {
+//		authortest.RunRepositoryTests(t, func(t *testing.T) author.Repository {
+//			return newEmptyRepository(t)
+//		})
+//	}
+package authortest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// Factory returns an empty repository. It is called once per subtest; use
+// t.Cleanup to release resources.
+type Factory func(t *testing.T) author.Repository
+
+// RunRepositoryTests runs the conformance suite against repositories built by
+// newRepo. Each behaviour runs as its own subtest on a fresh repository.
+func RunRepositoryTests(t *testing.T, newRepo Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo author.Repository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"NullBio", testNullBio},
+		{"GetNotFound", testGetNotFound},
+		{"GetAuthorsByIDs", testGetAuthorsByIDs},
+		{"ListOrdering", testListOrdering},
+		{"ListAuthorsPage", testListAuthorsPage},
+		{"Update", testUpdate},
+		{"Patch", testPatch},
+		{"Delete", testDelete},
+		{"Restore", testRestore},
+		{"Purge", testPurge},
+		{"Upsert", testUpsert},
+		{"Search", testSearch},
+		{"BulkCreateAndStream", testBulkCreateAndStream},
+		{"ConcurrentCreates", testConcurrentCreates},
+		{"ConcurrentUpdates", testConcurrentUpdates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t))
+		})
+	}
+}
+
+func text(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: true}
+}
+
+func mustCreate(t *testing.T, repo author.Repository, name string, bio pgtype.Text) *author.Author {
+	t.Helper()
+	a, err := repo.CreateAuthor(context.Background(), author.CreateAuthorParams{Name: name, Bio: bio})
+	if err != nil {
+		t.Fatalf("CreateAuthor(%q): %v", name, err)
+	}
+	return a
+}
+
+func names(authors []*author.Author) []string {
+	result := make([]string, len(authors))
+	for i, a := range authors {
+		result[i] = a.Name
+	}
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testCreateAndGet(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	created := mustCreate(t, repo, "Alice", text("Writes things"))
+	if created.ID == 0 {
+		t.Error("expected a non-zero ID")
+	}
+	if created.Version != 1 {
+		t.Errorf("expected version 1, got %d", created.Version)
+	}
+	if created.DeletedAt.Valid {
+		t.Error("expected a new author not to be deleted")
+	}
+
+	got, err := repo.GetAuthor(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor: %v", err)
+	}
+	if got.ID != created.ID || got.Name != "Alice" || got.Bio != text("Writes things") || got.Version != 1 {
+		t.Errorf("GetAuthor returned %+v, want %+v", got, created)
+	}
+
+	second := mustCreate(t, repo, "Bob", pgtype.Text{})
+	if second.ID == created.ID {
+		t.Errorf("expected distinct IDs, both are %d", second.ID)
+	}
+}
+
+func testNullBio(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	null := mustCreate(t, repo, "Null", pgtype.Text{})
+	empty := mustCreate(t, repo, "Empty", text(""))
+
+	got, err := repo.GetAuthor(ctx, null.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor: %v", err)
+	}
+	if got.Bio.Valid {
+		t.Errorf("expected NULL bio, got %+v", got.Bio)
+	}
+
+	got, err = repo.GetAuthor(ctx, empty.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor: %v", err)
+	}
+	if !got.Bio.Valid || got.Bio.String != "" {
+		t.Errorf("expected empty non-NULL bio, got %+v", got.Bio)
+	}
+
+	err = repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: empty.ID, Name: "Empty"})
+	if err != nil {
+		t.Fatalf("UpdateAuthor: %v", err)
+	}
+	if got, _ = repo.GetAuthor(ctx, empty.ID); got == nil || got.Bio.Valid {
+		t.Errorf("expected bio to be cleared to NULL, got %+v", got)
+	}
+}
+
+func testGetNotFound(t *testing.T, repo author.Repository) {
+	a, err := repo.GetAuthor(context.Background(), 987654321)
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+	if a != nil {
+		t.Errorf("expected nil author, got %+v", a)
+	}
+}
+
+func testGetAuthorsByIDs(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	alice := mustCreate(t, repo, "Alice", pgtype.Text{})
+	bob := mustCreate(t, repo, "Bob", pgtype.Text{})
+	carol := mustCreate(t, repo, "Carol", pgtype.Text{})
+	if err := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: bob.ID}); err != nil {
+		t.Fatalf("DeleteAuthor: %v", err)
+	}
+
+	authors, missing, err := repo.GetAuthorsByIDs(ctx, []int64{carol.ID, 987654321, alice.ID, bob.ID, carol.ID})
+	if err != nil {
+		t.Fatalf("GetAuthorsByIDs: %v", err)
+	}
+	if got := names(authors); !equal(got, []string{"Carol", "Alice"}) {
+		t.Errorf("expected authors in request order, got %v", got)
+	}
+	if len(missing) != 2 || missing[0] != 987654321 || missing[1] != bob.ID {
+		t.Errorf("expected missing [987654321 %d], got %v", bob.ID, missing)
+	}
+
+	authors, missing, err = repo.GetAuthorsByIDs(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetAuthorsByIDs(nil): %v", err)
+	}
+	if len(authors) != 0 || len(missing) != 0 {
+		t.Errorf("expected nothing for no IDs, got %v and %v", authors, missing)
+	}
+}
+
+func testListOrdering(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	for _, name := range []string{"Carol", "Alice", "Bob"} {
+		mustCreate(t, repo, name, pgtype.Text{})
+	}
+
+	all, err := repo.ListAuthors(ctx)
+	if err != nil {
+		t.Fatalf("ListAuthors: %v", err)
+	}
+	if got := names(all); !equal(got, []string{"Alice", "Bob", "Carol"}) {
+		t.Errorf("expected authors ordered by name, got %v", got)
+	}
+}
+
+func testListAuthorsPage(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	mustCreate(t, repo, "Carol", pgtype.Text{})
+	bob1 := mustCreate(t, repo, "Bob", pgtype.Text{})
+	mustCreate(t, repo, "Alice", pgtype.Text{})
+	bob2 := mustCreate(t, repo, "Bob", pgtype.Text{})
+
+	first, err := repo.ListAuthorsPage(ctx, author.ListAuthorsPageParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListAuthorsPage: %v", err)
+	}
+	if len(first) != 2 || first[0].Name != "Alice" || first[1].ID != bob1.ID {
+		t.Fatalf("unexpected first page: %v", names(first))
+	}
+
+	last := first[len(first)-1]
+	second, err := repo.ListAuthorsPage(ctx, author.ListAuthorsPageParams{
+		After: &author.Cursor{Name: last.Name, ID: last.ID},
+		Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("ListAuthorsPage: %v", err)
+	}
+	if len(second) != 2 || second[0].ID != bob2.ID || second[1].Name != "Carol" {
+		t.Errorf("unexpected second page: %v", names(second))
+	}
+}
+
+func testUpdate(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	a := mustCreate(t, repo, "Alice", pgtype.Text{})
+
+	err := repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: a.ID, Name: "Alicia", Bio: text("Renamed"), Version: a.Version})
+	if err != nil {
+		t.Fatalf("UpdateAuthor: %v", err)
+	}
+	got, err := repo.GetAuthor(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor: %v", err)
+	}
+	if got.Name != "Alicia" || got.Bio != text("Renamed") || got.Version != a.Version+1 {
+		t.Errorf("unexpected author after update: %+v", got)
+	}
+
+	err = repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: a.ID, Name: "Stale", Version: a.Version})
+	if !errors.Is(err, apperrors.PreconditionFailedError) {
+		t.Errorf("expected PreconditionFailedError for a stale version, got %v", err)
+	}
+
+	err = repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: a.ID, Name: "Forced"})
+	if err != nil {
+		t.Errorf("expected version 0 to skip the check, got %v", err)
+	}
+
+	err = repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: 987654321, Name: "Ghost"})
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
+
+func testPatch(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	a := mustCreate(t, repo, "Alice", text("Writes things"))
+
+	err := repo.PatchAuthor(ctx, author.PatchAuthorParams{ID: a.ID, Name: author.PatchValue("Alicia"), Version: a.Version})
+	if err != nil {
+		t.Fatalf("PatchAuthor(name): %v", err)
+	}
+	got, err := repo.GetAuthor(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor: %v", err)
+	}
+	if got.Name != "Alicia" || got.Bio != text("Writes things") || got.Version != a.Version+1 {
+		t.Errorf("expected only the name to change, got %+v", got)
+	}
+
+	err = repo.PatchAuthor(ctx, author.PatchAuthorParams{ID: a.ID, Bio: author.PatchValue(""), Version: got.Version})
+	if err != nil {
+		t.Fatalf("PatchAuthor(empty bio): %v", err)
+	}
+	if got, _ = repo.GetAuthor(ctx, a.ID); got == nil || got.Name != "Alicia" || got.Bio != text("") {
+		t.Errorf("expected an empty non-NULL bio, got %+v", got)
+	}
+
+	err = repo.PatchAuthor(ctx, author.PatchAuthorParams{ID: a.ID, Bio: author.PatchNull[string]()})
+	if err != nil {
+		t.Fatalf("PatchAuthor(null bio): %v", err)
+	}
+	if got, _ = repo.GetAuthor(ctx, a.ID); got == nil || got.Bio.Valid || got.Version != a.Version+3 {
+		t.Errorf("expected the bio to be cleared to NULL, got %+v", got)
+	}
+
+	err = repo.PatchAuthor(ctx, author.PatchAuthorParams{ID: a.ID, Name: author.PatchValue("Stale"), Version: a.Version})
+	if !errors.Is(err, apperrors.PreconditionFailedError) {
+		t.Errorf("expected PreconditionFailedError for a stale version, got %v", err)
+	}
+
+	err = repo.PatchAuthor(ctx, author.PatchAuthorParams{ID: 987654321, Name: author.PatchValue("Ghost")})
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
+
+func testDelete(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	a := mustCreate(t, repo, "Alice", pgtype.Text{})
+	b := mustCreate(t, repo, "Bob", pgtype.Text{})
+
+	err := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: a.ID, Version: a.Version + 1})
+	if !errors.Is(err, apperrors.PreconditionFailedError) {
+		t.Errorf("expected PreconditionFailedError for a stale version, got %v", err)
+	}
+	if err := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: a.ID, Version: a.Version}); err != nil {
+		t.Fatalf("DeleteAuthor: %v", err)
+	}
+
+	if _, err := repo.GetAuthor(ctx, a.ID); !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected deleted author to be hidden from GetAuthor, got %v", err)
+	}
+	all, err := repo.ListAuthors(ctx)
+	if err != nil {
+		t.Fatalf("ListAuthors: %v", err)
+	}
+	if len(all) != 1 || all[0].ID != b.ID {
+		t.Errorf("expected only Bob to be listed, got %v", names(all))
+	}
+	withDeleted, err := repo.ListAuthorsPage(ctx, author.ListAuthorsPageParams{Limit: 10, IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("ListAuthorsPage: %v", err)
+	}
+	if len(withDeleted) != 2 || !withDeleted[0].DeletedAt.Valid || withDeleted[0].Version != a.Version+1 {
+		t.Errorf("expected the deleted author with include_deleted, got %+v", withDeleted)
+	}
+
+	err = repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: a.ID})
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected NotFoundError deleting twice, got %v", err)
+	}
+}
+
+func testRestore(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	a := mustCreate(t, repo, "Alice", pgtype.Text{})
+
+	if err := repo.RestoreAuthor(ctx, a.ID); !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected NotFoundError restoring a live author, got %v", err)
+	}
+	if err := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: a.ID}); err != nil {
+		t.Fatalf("DeleteAuthor: %v", err)
+	}
+	if err := repo.RestoreAuthor(ctx, a.ID); err != nil {
+		t.Fatalf("RestoreAuthor: %v", err)
+	}
+	got, err := repo.GetAuthor(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor after restore: %v", err)
+	}
+	if got.DeletedAt.Valid || got.Version != a.Version+2 {
+		t.Errorf("unexpected author after restore: %+v", got)
+	}
+}
+
+func testPurge(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	gone := mustCreate(t, repo, "Gone", pgtype.Text{})
+	kept := mustCreate(t, repo, "Kept", pgtype.Text{})
+	if err := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: gone.ID}); err != nil {
+		t.Fatalf("DeleteAuthor: %v", err)
+	}
+
+	n, err := repo.PurgeDeletedAuthors(ctx, time.Now().Add(-time.Hour))
+	if err != nil || n != 0 {
+		t.Errorf("expected nothing purged before retention, got %d, %v", n, err)
+	}
+	n, err = repo.PurgeDeletedAuthors(ctx, time.Now().Add(time.Hour))
+	if err != nil || n != 1 {
+		t.Errorf("expected 1 purged author, got %d, %v", n, err)
+	}
+	if err := repo.RestoreAuthor(ctx, gone.ID); !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected purged author to be gone, got %v", err)
+	}
+	if _, err := repo.GetAuthor(ctx, kept.ID); err != nil {
+		t.Errorf("expected live author to survive purge, got %v", err)
+	}
+}
+
+func testUpsert(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	other := mustCreate(t, repo, "Other", pgtype.Text{})
+
+	created, isNew, err := repo.UpsertAuthor(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "Alice", Bio: text("First")})
+	if err != nil {
+		t.Fatalf("UpsertAuthor: %v", err)
+	}
+	if !isNew || created.ID == other.ID || created.ExternalID != text("ext-1") || created.Version != 1 {
+		t.Errorf("expected a new author with external ID ext-1, got %+v (created %t)", created, isNew)
+	}
+
+	if err := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: created.ID}); err != nil {
+		t.Fatalf("DeleteAuthor: %v", err)
+	}
+	updated, isNew, err := repo.UpsertAuthor(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "Alicia"})
+	if err != nil {
+		t.Fatalf("UpsertAuthor: %v", err)
+	}
+	if isNew || updated.ID != created.ID || updated.Name != "Alicia" || updated.Bio.Valid || updated.Version != created.Version+2 {
+		t.Errorf("expected the existing author to be updated, got %+v (created %t)", updated, isNew)
+	}
+	got, err := repo.GetAuthor(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("expected the upsert to restore the deleted author, got %v", err)
+	}
+	if *got != *updated {
+		t.Errorf("expected GetAuthor to return %+v, got %+v", updated, got)
+	}
+	if got, _ := repo.GetAuthor(ctx, other.ID); got.ExternalID.Valid {
+		t.Errorf("expected an author created without external ID to have none, got %+v", got)
+	}
+}
+
+func testSearch(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	alice := mustCreate(t, repo, "Alice", text("Writes about gardening"))
+	alfred := mustCreate(t, repo, "Alfred", text("Gardening, gardening and more gardening"))
+	mustCreate(t, repo, "Bob", text("Gardening expert"))
+	alma := mustCreate(t, repo, "Alma", pgtype.Text{})
+	mustCreate(t, repo, "Al_x", text("Underscore in name"))
+
+	ranked, err := repo.SearchAuthors(ctx, author.SearchAuthorsParams{Query: "gardening", NamePrefix: "Al", Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchAuthors: %v", err)
+	}
+	if len(ranked) != 2 || ranked[0].ID != alfred.ID || ranked[1].ID != alice.ID {
+		t.Errorf("expected Alfred then Alice, got %v", names(ranked))
+	}
+
+	noBio, err := repo.SearchAuthors(ctx, author.SearchAuthorsParams{HasBio: pgtype.Bool{Bool: false, Valid: true}, Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchAuthors: %v", err)
+	}
+	if len(noBio) != 1 || noBio[0].ID != alma.ID {
+		t.Errorf("expected only Alma, got %v", names(noBio))
+	}
+
+	literal, err := repo.SearchAuthors(ctx, author.SearchAuthorsParams{NamePrefix: "Al_", Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchAuthors: %v", err)
+	}
+	if got := names(literal); !equal(got, []string{"Al_x"}) {
+		t.Errorf("expected name prefix to match literally, got %v", got)
+	}
+
+	limited, err := repo.SearchAuthors(ctx, author.SearchAuthorsParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchAuthors: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("expected limit to be honoured, got %d results", len(limited))
+	}
+}
+
+func testBulkCreateAndStream(t *testing.T, repo author.Repository) {
+	ctx := context.Background()
+	created, err := repo.BulkCreateAuthors(ctx, []author.CreateAuthorParams{
+		{Name: "Zed", Bio: text("Last by name")},
+		{Name: "Amy"},
+		{Name: "Max"},
+	})
+	if err != nil {
+		t.Fatalf("BulkCreateAuthors: %v", err)
+	}
+	if got := names(created); !equal(got, []string{"Zed", "Amy", "Max"}) {
+		t.Errorf("expected the new authors in input order, got %v", got)
+	}
+	for _, a := range created {
+		if a.ID == 0 || a.Version != 1 {
+			t.Errorf("expected a stored author, got %+v", a)
+		}
+	}
+
+	var streamed []*author.Author
+	err = repo.StreamAuthors(ctx, func(a *author.Author) error {
+		streamed = append(streamed, a)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamAuthors: %v", err)
+	}
+	if got := names(streamed); !equal(got, []string{"Zed", "Amy", "Max"}) {
+		t.Errorf("expected authors streamed in ID order, got %v", got)
+	}
+	if streamed[0].Bio != text("Last by name") || streamed[1].Bio.Valid {
+		t.Errorf("unexpected bios: %+v, %+v", streamed[0].Bio, streamed[1].Bio)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.StreamAuthors(ctx, func(*author.Author) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected streaming to stop at the first error, got %v after %d calls", err, calls)
+	}
+}
+
+func testConcurrentCreates(t *testing.T, repo author.Repository) {
+	const writers = 20
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	ids := make(chan int64, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a, err := repo.CreateAuthor(ctx, author.CreateAuthorParams{Name: "Writer"})
+			if err != nil {
+				t.Errorf("CreateAuthor: %v", err)
+				return
+			}
+			ids <- a.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int64]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("ID %d assigned twice", id)
+		}
+		seen[id] = true
+	}
+	all, err := repo.ListAuthors(ctx)
+	if err != nil {
+		t.Fatalf("ListAuthors: %v", err)
+	}
+	if len(all) != writers {
+		t.Errorf("expected %d authors, got %d", writers, len(all))
+	}
+}
+
+func testConcurrentUpdates(t *testing.T, repo author.Repository) {
+	const writers = 10
+	ctx := context.Background()
+	a := mustCreate(t, repo, "Contended", pgtype.Text{})
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: a.ID, Name: "Winner", Version: a.Version})
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !errors.Is(err, apperrors.PreconditionFailedError):
+				t.Errorf("expected PreconditionFailedError for losers, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := succeeded.Load(); got != 1 {
+		t.Errorf("expected exactly one update to win, got %d", got)
+	}
+	got, err := repo.GetAuthor(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor: %v", err)
+	}
+	if got.Version != a.Version+1 {
+		t.Errorf("expected version %d, got %d", a.Version+1, got.Version)
+	}
+}
@@ -0,0 +1,28 @@
+package author
+
+import (
+	"context"
+	"time"
+)
+
+// Change is a live notification that an author changed. ID identifies the
+// change within its feed and is what a subscriber passes back to resume.
+type Change struct {
+	ID       string    `json:"-"`
+	Type     EventType `json:"type"`
+	AuthorID int64     `json:"author_id"`
+	Version  int32     `json:"version"`
+	TenantID string    `json:"tenant_id,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// ChangeFeed broadcasts author changes to live subscribers. Subscribers only
+// receive the changes of the tenant of their context.
+type ChangeFeed interface {
+	// Subscribe delivers the changes after lastID (or, if lastID is empty,
+	// those made from now on) until ctx is done. gap reports that some
+	// changes after lastID are no longer available, so the subscriber should
+	// reload what it shows. The channel is closed when the subscription
+	// ends, including when the subscriber falls too far behind.
+	Subscribe(ctx context.Context, lastID string) (changes <-chan Change, gap bool)
+}
@@ -2,11 +2,17 @@ package author
 
 import "github.com/jackc/pgx/v5/pgtype"
 
-// Author represents an author in the domain model.
+// Author represents an author in the domain model. ExternalID, if set,
+// identifies the author in an upstream system and is unique. DeletedAt is set
+// once the author has been soft-deleted. Version is incremented on every
+// change and is used for optimistic concurrency control.
 type Author struct {
-	ID   int64
-	Name string
-	Bio  pgtype.Text
+	ID         int64
+	Name       string
+	Bio        pgtype.Text
+	ExternalID pgtype.Text
+	DeletedAt  pgtype.Timestamptz
+	Version    int32
 }
 
 // CreateAuthorParams holds parameters for creating an author.
@@ -15,9 +21,60 @@ type CreateAuthorParams struct {
 	Bio  pgtype.Text
 }
 
-// UpdateAuthorParams holds parameters for updating an author.
+// UpsertAuthorParams holds parameters for creating or updating the author
+// with ExternalID.
+type UpsertAuthorParams struct {
+	ExternalID string
+	Name       string
+	Bio        pgtype.Text
+}
+
+// UpdateAuthorParams holds parameters for updating an author. Version is the
+// version the caller last saw; 0 skips the concurrency check.
 type UpdateAuthorParams struct {
-	ID   int64
+	ID      int64
+	Name    string
+	Bio     pgtype.Text
+	Version int32
+}
+
+// PatchAuthorParams holds a partial update of an author: only the fields
+// that are set are changed, and a null Bio clears it. Version is the version
+// the caller last saw; 0 skips the concurrency check.
+type PatchAuthorParams struct {
+	ID      int64
+	Name    PatchField[string]
+	Bio     PatchField[string]
+	Version int32
+}
+
+// DeleteAuthorParams holds parameters for deleting an author. Version is the
+// version the caller last saw; 0 skips the concurrency check.
+type DeleteAuthorParams struct {
+	ID      int64
+	Version int32
+}
+
+// Cursor identifies a position in the (name, id) ordering of authors.
+type Cursor struct {
 	Name string
-	Bio  pgtype.Text
+	ID   int64
+}
+
+// ListAuthorsPageParams holds parameters for fetching a page of authors.
+// A nil After starts from the beginning. Soft-deleted authors are only
+// returned when IncludeDeleted is set.
+type ListAuthorsPageParams struct {
+	After          *Cursor
+	Limit          int32
+	IncludeDeleted bool
+}
+
+// SearchAuthorsParams holds filters for searching authors. Empty Query and
+// NamePrefix and a null HasBio disable the corresponding filter.
+type SearchAuthorsParams struct {
+	Query      string
+	NamePrefix string
+	HasBio     pgtype.Bool
+	Limit      int32
 }
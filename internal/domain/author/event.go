@@ -0,0 +1,40 @@
+package author
+
+import (
+	"context"
+	"time"
+)
+
+// EventType names a kind of change to an author.
+type EventType string
+
+// Author event types.
+const (
+	EventCreated  EventType = "author.created"
+	EventUpdated  EventType = "author.updated"
+	EventDeleted  EventType = "author.deleted"
+	EventRestored EventType = "author.restored"
+)
+
+// Event describes a change to an author. Author is the state after the
+// change and is nil for deletions; its Version orders events for the same
+// author, since delivery order is not guaranteed.
+type Event struct {
+	Type       EventType
+	AuthorID   int64
+	Author     *Author
+	OccurredAt time.Time
+}
+
+// NewEvent returns an Event of type t about author id, occurring now. a may
+// be nil.
+func NewEvent(t EventType, id int64, a *Author) Event {
+	return Event{Type: t, AuthorID: id, Author: a, OccurredAt: time.Now().UTC()}
+}
+
+// EventRecorder records author events for publication. Implementations take
+// part in the transaction carried by ctx, so an event is kept if and only if
+// the change it describes commits.
+type EventRecorder interface {
+	RecordEvent(ctx context.Context, e Event) error
+}
@@ -0,0 +1,40 @@
+package author
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// PatchField is a member of a JSON Merge Patch (RFC 7396). The zero value is
+// an absent member, which leaves the target unchanged; a present member
+// either sets a new value or, when Null, removes the current one.
+type PatchField[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// PatchValue returns a PatchField setting v.
+func PatchValue[T any](v T) PatchField[T] {
+	return PatchField[T]{Set: true, Value: v}
+}
+
+// PatchNull returns a PatchField removing the current value.
+func PatchNull[T any]() PatchField[T] {
+	return PatchField[T]{Set: true, Null: true}
+}
+
+// UnmarshalJSON implements json.Unmarshaler. encoding/json only calls it for
+// members present in the document, so absent members stay unset.
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*f = PatchNull[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = PatchValue(v)
+	return nil
+}
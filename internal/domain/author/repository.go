@@ -1,12 +1,60 @@
 package author
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
-// Repository defines the interface for author data access.
+// Repository defines the interface for author data access. Reads exclude
+// soft-deleted authors unless stated otherwise; DeleteAuthor soft-deletes.
+// GetAuthorsByIDs returns the authors found in the order of ids, each at most
+// once, and the IDs that were not found (see MatchIDs). PatchAuthor changes
+// only the fields that are set. UpdateAuthor, PatchAuthor and DeleteAuthor
+// return errors.PreconditionFailedError when the expected version no longer
+// matches and errors.NotFoundError when the author does not exist.
+// UpsertAuthor creates or updates the author with the given external ID
+// regardless of its version, restoring it if it was soft-deleted, and
+// reports whether it was created. BulkCreateAuthors inserts all rows or none
+// and returns the new authors in the order of params. StreamAuthors calls fn for each
+// live author in ID order and stops at the first error fn returns.
 type Repository interface {
 	GetAuthor(ctx context.Context, id int64) (*Author, error)
+	GetAuthorsByIDs(ctx context.Context, ids []int64) (authors []*Author, missing []int64, err error)
 	ListAuthors(ctx context.Context) ([]*Author, error)
+	ListAuthorsPage(ctx context.Context, params ListAuthorsPageParams) ([]*Author, error)
+	SearchAuthors(ctx context.Context, params SearchAuthorsParams) ([]*Author, error)
+	StreamAuthors(ctx context.Context, fn func(*Author) error) error
 	CreateAuthor(ctx context.Context, params CreateAuthorParams) (*Author, error)
+	UpsertAuthor(ctx context.Context, params UpsertAuthorParams) (*Author, bool, error)
+	BulkCreateAuthors(ctx context.Context, params []CreateAuthorParams) ([]*Author, error)
 	UpdateAuthor(ctx context.Context, params UpdateAuthorParams) error
-	DeleteAuthor(ctx context.Context, id int64) error
+	PatchAuthor(ctx context.Context, params PatchAuthorParams) error
+	DeleteAuthor(ctx context.Context, params DeleteAuthorParams) error
+	RestoreAuthor(ctx context.Context, id int64) error
+	PurgeDeletedAuthors(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// MatchIDs orders found, the authors fetched for ids in any order, by the
+// first occurrence of their ID in ids, and returns the IDs with no author in
+// found. Duplicate IDs yield a single author or a single missing entry.
+func MatchIDs(ids []int64, found []*Author) (authors []*Author, missing []int64) {
+	byID := make(map[int64]*Author, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+	authors = []*Author{}
+	missing = []int64{}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if a, ok := byID[id]; ok {
+			authors = append(authors, a)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return authors, missing
 }
@@ -0,0 +1,29 @@
+package book
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+// Book represents a book in the domain model.
+type Book struct {
+	ID          int64
+	AuthorID    int64
+	Title       string
+	ISBN        pgtype.Text
+	PublishedAt pgtype.Date
+}
+
+// CreateBookParams holds parameters for creating a book.
+type CreateBookParams struct {
+	AuthorID    int64
+	Title       string
+	ISBN        pgtype.Text
+	PublishedAt pgtype.Date
+}
+
+// UpdateBookParams holds parameters for updating a book.
+type UpdateBookParams struct {
+	ID          int64
+	AuthorID    int64
+	Title       string
+	ISBN        pgtype.Text
+	PublishedAt pgtype.Date
+}
@@ -0,0 +1,14 @@
+package book
+
+import "context"
+
+// Repository defines the interface for book data access. GetBook, UpdateBook
+// and DeleteBook return errors.NotFoundError when the book does not exist.
+type Repository interface {
+	GetBook(ctx context.Context, id int64) (*Book, error)
+	ListBooks(ctx context.Context) ([]*Book, error)
+	ListBooksByAuthor(ctx context.Context, authorID int64) ([]*Book, error)
+	CreateBook(ctx context.Context, params CreateBookParams) (*Book, error)
+	UpdateBook(ctx context.Context, params UpdateBookParams) error
+	DeleteBook(ctx context.Context, id int64) error
+}
@@ -0,0 +1,46 @@
+// Package idempotency defines how the responses to mutating requests are
+// remembered, so that a client retrying a request with the same
+// Idempotency-Key gets the original response instead of repeating the
+// change.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Response is a stored response to replay.
+type Response struct {
+	Status int
+	Header map[string][]string
+	Body   []byte
+}
+
+// Record is the state of an idempotency key.
+type Record struct {
+	Key string
+	// Fingerprint identifies the request that claimed the key; a retry
+	// must present the same one.
+	Fingerprint string
+	// Response is nil while the request that claimed the key is still in
+	// progress.
+	Response  *Response
+	ExpiresAt time.Time
+}
+
+// Store persists idempotency keys. Keys are scoped to the tenant of the
+// context (see tenant.With).
+type Store interface {
+	// Reserve claims key for the request identified by fingerprint until
+	// expiresAt. It reports false, with the current record, when the key is
+	// already held by a request that has not expired at now.
+	Reserve(ctx context.Context, key, fingerprint string, now, expiresAt time.Time) (*Record, bool, error)
+	// Complete stores the response to the request holding key.
+	Complete(ctx context.Context, key string, resp Response) error
+	// Release gives up a key whose request has not completed, so that it
+	// can be retried.
+	Release(ctx context.Context, key string) error
+	// DeleteExpired deletes the keys that expired at or before now and
+	// returns how many it deleted.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,39 @@
+// Package tenant scopes requests to the publisher (tenant) owning the data.
+package tenant
+
+import (
+	"context"
+	"regexp"
+
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// All is a pseudo-tenant granting read and delete access to every tenant's
+// rows. It is for maintenance tools such as cmd/purge and is never accepted
+// from a request (Validate rejects it).
+const All = "*"
+
+// validID matches tenant IDs; the database enforces the same pattern.
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type tenantKey struct{}
+
+// With returns a copy of ctx scoped to tenant id.
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant ctx is scoped to, or "" if none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// Validate reports whether id is a well-formed tenant ID: 1 to 64 ASCII
+// letters, digits, '-' or '_'.
+func Validate(id string) error {
+	if !validID.MatchString(id) {
+		return apperrors.ValidationError("invalid tenant id")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package transaction
+
+import "context"
+
+// IsolationLevel is the SQL transaction isolation level.
+type IsolationLevel string
+
+// Supported isolation levels. The empty value uses the database default.
+const (
+	Default         IsolationLevel = ""
+	ReadUncommitted IsolationLevel = "read uncommitted"
+	ReadCommitted   IsolationLevel = "read committed"
+	RepeatableRead  IsolationLevel = "repeatable read"
+	Serializable    IsolationLevel = "serializable"
+)
+
+// Options configures a transaction.
+type Options struct {
+	IsoLevel IsolationLevel
+	ReadOnly bool
+}
+
+// Manager runs functions inside a database transaction. Repositories called
+// with the context passed to fn take part in that transaction.
+type Manager interface {
+	// RunInTx runs fn in a transaction using the manager's default options.
+	// The transaction is committed if fn returns nil and rolled back otherwise.
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// RunInTxWithOptions runs fn in a transaction using opts.
+	RunInTxWithOptions(ctx context.Context, opts Options, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,343 @@
+// Package cache provides caching decorators for the domain repositories.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+	"golang.org/x/sync/singleflight"
+)
+
+// Stats is a snapshot of an AuthorRepository's cache counters.
+type Stats struct {
+	Hits         uint64 `json:"hits"`
+	NegativeHits uint64 `json:"negative_hits"`
+	Misses       uint64 `json:"misses"`
+	Evictions    uint64 `json:"evictions"`
+	Entries      int    `json:"entries"`
+}
+
+// AuthorRepository decorates an author.Repository with a bounded LRU cache
+// for GetAuthor and GetAuthorsByIDs. Found authors are kept for ttl and
+// misses (NotFound) for negativeTTL; writes through the decorator invalidate
+// the affected entries when they commit.
+// Concurrent misses for the same ID share a single call to the underlying
+// repository, which is not canceled when one of the callers gives up. Misses
+// are loaded from the primary (see database.WithPrimary): a lagging replica
+// could return the row as it was before an invalidating write, and it would
+// then be cached for ttl. Writes made elsewhere (another process, or a
+// transaction this decorator did not see) become visible once the entry
+// expires.
+//
+// Entries are kept per tenant (see tenant.FromContext), so a tenant is never
+// served another tenant's author. Reads inside a transaction bypass the cache
+// so uncommitted rows are never cached. All other methods are passed through.
+type AuthorRepository struct {
+	author.Repository
+
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[int64]map[string]*list.Element // by author ID, then tenant
+	lru     *list.List                         // front is most recently used
+	epoch   uint64                             // bumped on every invalidation
+	group   singleflight.Group
+
+	hits, negativeHits, misses, evictions atomic.Uint64
+}
+
+// entry is a cached GetAuthor result; a nil author records NotFound.
+type entry struct {
+	id      int64
+	tenant  string
+	author  *author.Author
+	expires time.Time
+}
+
+// NewAuthorRepository wraps next with a cache holding at most size authors.
+func NewAuthorRepository(next author.Repository, size int, ttl, negativeTTL time.Duration) *AuthorRepository {
+	return &AuthorRepository{
+		Repository:  next,
+		size:        size,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		entries:     make(map[int64]map[string]*list.Element),
+		lru:         list.New(),
+	}
+}
+
+// Stats returns the current cache counters.
+func (r *AuthorRepository) Stats() Stats {
+	r.mu.Lock()
+	n := r.lru.Len()
+	r.mu.Unlock()
+	return Stats{
+		Hits:         r.hits.Load(),
+		NegativeHits: r.negativeHits.Load(),
+		Misses:       r.misses.Load(),
+		Evictions:    r.evictions.Load(),
+		Entries:      n,
+	}
+}
+
+// GetAuthor retrieves a single author by ID, from the cache when possible.
+func (r *AuthorRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
+	if _, ok := database.TxFromContext(ctx); ok {
+		return r.Repository.GetAuthor(ctx, id)
+	}
+
+	t := tenant.FromContext(ctx)
+	if e, ok := r.lookup(t, id); ok {
+		if e.author == nil {
+			r.negativeHits.Add(1)
+			return nil, apperrors.NotFoundError
+		}
+		r.hits.Add(1)
+		return clone(e.author), nil
+	}
+	r.misses.Add(1)
+
+	// The load is shared, so it must outlive the caller that started it;
+	// WithoutCancel keeps the tenant and routing values of ctx.
+	load := context.WithoutCancel(ctx)
+	ch := r.group.DoChan(flightKey(t, id), func() (any, error) {
+		r.mu.Lock()
+		epoch := r.epoch
+		r.mu.Unlock()
+
+		a, err := r.Repository.GetAuthor(database.WithPrimary(load), id)
+		switch {
+		case err == nil:
+			r.store(epoch, t, id, a, r.ttl)
+		case errors.Is(err, apperrors.NotFoundError):
+			r.store(epoch, t, id, nil, r.negativeTTL)
+		}
+		return a, err
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return clone(res.Val.(*author.Author)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GetAuthorsByIDs retrieves the authors with the given IDs, fetching those
+// not cached in a single call to the underlying repository.
+func (r *AuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	if _, ok := database.TxFromContext(ctx); ok {
+		return r.Repository.GetAuthorsByIDs(ctx, ids)
+	}
+
+	t := tenant.FromContext(ctx)
+	var found []*author.Author
+	var uncached []int64
+	for _, id := range ids {
+		e, ok := r.lookup(t, id)
+		switch {
+		case !ok:
+			r.misses.Add(1)
+			uncached = append(uncached, id)
+		case e.author == nil:
+			r.negativeHits.Add(1)
+		default:
+			r.hits.Add(1)
+			found = append(found, clone(e.author))
+		}
+	}
+
+	if len(uncached) > 0 {
+		r.mu.Lock()
+		epoch := r.epoch
+		r.mu.Unlock()
+
+		fetched, missing, err := r.Repository.GetAuthorsByIDs(database.WithPrimary(ctx), uncached)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, a := range fetched {
+			r.store(epoch, t, a.ID, a, r.ttl)
+			found = append(found, a)
+		}
+		for _, id := range missing {
+			r.store(epoch, t, id, nil, r.negativeTTL)
+		}
+	}
+	authors, missing := author.MatchIDs(ids, found)
+	return authors, missing, nil
+}
+
+// CreateAuthor creates a new author and drops any cached miss for its ID.
+func (r *AuthorRepository) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
+	a, err := r.Repository.CreateAuthor(ctx, params)
+	if err == nil {
+		r.invalidate(ctx, a.ID)
+	}
+	return a, err
+}
+
+// UpsertAuthor creates or updates an author and invalidates its entry.
+func (r *AuthorRepository) UpsertAuthor(ctx context.Context, params author.UpsertAuthorParams) (*author.Author, bool, error) {
+	a, created, err := r.Repository.UpsertAuthor(ctx, params)
+	if err == nil {
+		r.invalidate(ctx, a.ID)
+	}
+	return a, created, err
+}
+
+// BulkCreateAuthors inserts params and drops every cached miss, since the
+// new IDs are not known.
+func (r *AuthorRepository) BulkCreateAuthors(ctx context.Context, params []author.CreateAuthorParams) ([]*author.Author, error) {
+	created, err := r.Repository.BulkCreateAuthors(ctx, params)
+	r.invalidateMisses(ctx)
+	return created, err
+}
+
+// UpdateAuthor updates an existing author and invalidates its entry.
+func (r *AuthorRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
+	defer r.invalidate(ctx, params.ID)
+	return r.Repository.UpdateAuthor(ctx, params)
+}
+
+// PatchAuthor partially updates an author and invalidates its entry.
+func (r *AuthorRepository) PatchAuthor(ctx context.Context, params author.PatchAuthorParams) error {
+	defer r.invalidate(ctx, params.ID)
+	return r.Repository.PatchAuthor(ctx, params)
+}
+
+// DeleteAuthor soft-deletes an author and invalidates its entry.
+func (r *AuthorRepository) DeleteAuthor(ctx context.Context, params author.DeleteAuthorParams) error {
+	defer r.invalidate(ctx, params.ID)
+	return r.Repository.DeleteAuthor(ctx, params)
+}
+
+// RestoreAuthor restores a soft-deleted author and invalidates its entry.
+func (r *AuthorRepository) RestoreAuthor(ctx context.Context, id int64) error {
+	defer r.invalidate(ctx, id)
+	return r.Repository.RestoreAuthor(ctx, id)
+}
+
+// lookup returns the live entry for id in tenant t, marking it recently
+// used. Expired entries are removed.
+func (r *AuthorRepository) lookup(t string, id int64) (*entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	el, ok := r.entries[id][t]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !r.now().Before(e.expires) {
+		r.remove(el)
+		return nil, false
+	}
+	r.lru.MoveToFront(el)
+	return e, true
+}
+
+// store caches a for id in tenant t unless an invalidation happened since
+// epoch was read, in which case a may already be stale.
+func (r *AuthorRepository) store(epoch uint64, t string, id int64, a *author.Author, ttl time.Duration) {
+	if ttl <= 0 || r.size <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if epoch != r.epoch {
+		return
+	}
+	e := &entry{id: id, tenant: t, author: clone(a), expires: r.now().Add(ttl)}
+	if el, ok := r.entries[id][t]; ok {
+		el.Value = e
+		r.lru.MoveToFront(el)
+		return
+	}
+	if r.entries[id] == nil {
+		r.entries[id] = make(map[string]*list.Element)
+	}
+	r.entries[id][t] = r.lru.PushFront(e)
+	for r.lru.Len() > r.size {
+		r.remove(r.lru.Back())
+		r.evictions.Add(1)
+	}
+}
+
+// invalidate drops the entries for id in every tenant, as tenant.All may see
+// the author too, and makes in-flight loads for it uncacheable. Inside a
+// transaction this happens once it commits (see database.AfterCommit):
+// doing it earlier would let a concurrent read cache the row as it was
+// before the commit.
+func (r *AuthorRepository) invalidate(ctx context.Context, id int64) {
+	t := tenant.FromContext(ctx)
+	database.AfterCommit(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.epoch++
+		for _, el := range r.entries[id] {
+			r.remove(el)
+		}
+		r.group.Forget(flightKey(t, id))
+	})
+}
+
+// invalidateMisses drops every cached NotFound once the write made with ctx
+// has committed.
+func (r *AuthorRepository) invalidateMisses(ctx context.Context) {
+	database.AfterCommit(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.epoch++
+		for _, byTenant := range r.entries {
+			for _, el := range byTenant {
+				if el.Value.(*entry).author == nil {
+					r.remove(el)
+				}
+			}
+		}
+	})
+}
+
+// remove deletes el from the cache. The caller must hold r.mu.
+func (r *AuthorRepository) remove(el *list.Element) {
+	e := el.Value.(*entry)
+	delete(r.entries[e.id], e.tenant)
+	if len(r.entries[e.id]) == 0 {
+		delete(r.entries, e.id)
+	}
+	r.lru.Remove(el)
+}
+
+// flightKey identifies concurrent loads of id in tenant t that may share a
+// result.
+func flightKey(t string, id int64) string {
+	return t + "/" + strconv.FormatInt(id, 10)
+}
+
+// clone returns a copy of a so callers cannot modify cached values.
+func clone(a *author.Author) *author.Author {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	return &c
+}
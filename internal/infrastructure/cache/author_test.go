@@ -0,0 +1,420 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author/authortest"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// countingRepository counts GetAuthor and GetAuthorsByIDs calls, and those
+// of them not forced to the primary, and can hold GetAuthor calls until
+// release is closed.
+type countingRepository struct {
+	author.Repository
+	gets     atomic.Int64
+	batches  atomic.Int64
+	replicas atomic.Int64
+	release  chan struct{}
+}
+
+func (c *countingRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
+	c.gets.Add(1)
+	if !database.PrimaryRequested(ctx) {
+		c.replicas.Add(1)
+	}
+	if c.release != nil {
+		select {
+		case <-c.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return c.Repository.GetAuthor(ctx, id)
+}
+
+func (c *countingRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	c.batches.Add(1)
+	if !database.PrimaryRequested(ctx) {
+		c.replicas.Add(1)
+	}
+	return c.Repository.GetAuthorsByIDs(ctx, ids)
+}
+
+// pendingTx is a pgx.Tx that applies the writes queued on it when it
+// commits, like a database hiding uncommitted rows from other connections.
+type pendingTx struct {
+	pgx.Tx
+	writes []func()
+}
+
+func (tx *pendingTx) Commit(ctx context.Context) error {
+	for _, w := range tx.writes {
+		w()
+	}
+	return nil
+}
+
+func (tx *pendingTx) Rollback(ctx context.Context) error { return nil }
+
+type pendingBeginner struct{}
+
+func (pendingBeginner) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	return &pendingTx{}, nil
+}
+
+// isolatedRepository defers UpdateAuthor calls made in a transaction until
+// it commits.
+type isolatedRepository struct {
+	author.Repository
+}
+
+func (r isolatedRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
+	tx, ok := database.TxFromContext(ctx)
+	if !ok {
+		return r.Repository.UpdateAuthor(ctx, params)
+	}
+	tx.(*pendingTx).writes = append(tx.(*pendingTx).writes, func() {
+		r.Repository.UpdateAuthor(context.Background(), params)
+	})
+	return nil
+}
+
+func newTestCache(size int, authors ...*author.Author) (*AuthorRepository, *countingRepository) {
+	mem := memory.NewAuthorRepository()
+	mem.SeedAuthors(authors...)
+	next := &countingRepository{Repository: mem}
+	return NewAuthorRepository(next, size, time.Minute, time.Second), next
+}
+
+func TestAuthorRepository_Conformance(t *testing.T) {
+	authortest.RunRepositoryTests(t, func(t *testing.T) author.Repository {
+		return NewAuthorRepository(memory.NewAuthorRepository(), 100, time.Minute, time.Minute)
+	})
+}
+
+func TestAuthorRepository_CachesHits(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(10, &author.Author{Name: "Ann"})
+	ctx := context.Background()
+
+	// Act
+	first, _ := repo.GetAuthor(ctx, 1)
+	first.Name = "mutated"
+	second, err := repo.GetAuthor(ctx, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Name != "Ann" {
+		t.Errorf("expected cached copy to be unaffected, got %q", second.Name)
+	}
+	if got := next.gets.Load(); got != 1 {
+		t.Errorf("expected 1 underlying call, got %d", got)
+	}
+	if got := next.replicas.Load(); got != 0 {
+		t.Errorf("expected the miss to be loaded from the primary, got %d replica reads", got)
+	}
+	if s := repo.Stats(); s.Hits != 1 || s.Misses != 1 || s.Entries != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestAuthorRepository_CachesMisses(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(10)
+	ctx := context.Background()
+
+	// Act
+	_, err1 := repo.GetAuthor(ctx, 1)
+	_, err2 := repo.GetAuthor(ctx, 1)
+
+	// Assert
+	if !errors.Is(err1, apperrors.NotFoundError) || !errors.Is(err2, apperrors.NotFoundError) {
+		t.Fatalf("expected not found, got %v and %v", err1, err2)
+	}
+	if got := next.gets.Load(); got != 1 {
+		t.Errorf("expected 1 underlying call, got %d", got)
+	}
+	if s := repo.Stats(); s.NegativeHits != 1 {
+		t.Errorf("expected 1 negative hit, got %+v", s)
+	}
+}
+
+func TestAuthorRepository_GetAuthorsByIDsFetchesOnlyUncached(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(10, &author.Author{Name: "A"}, &author.Author{Name: "B"}, &author.Author{Name: "C"})
+	ctx := context.Background()
+	repo.GetAuthor(ctx, 2)
+	repo.GetAuthor(ctx, 9)
+
+	// Act
+	first, firstMissing, err := repo.GetAuthorsByIDs(ctx, []int64{3, 9, 2, 1})
+	second, secondMissing, _ := repo.GetAuthorsByIDs(ctx, []int64{1, 2, 3, 9})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first) != 3 || first[0].Name != "C" || first[1].Name != "B" || first[2].Name != "A" {
+		t.Errorf("expected authors in request order, got %+v", first)
+	}
+	if len(firstMissing) != 1 || firstMissing[0] != 9 || len(secondMissing) != 1 || secondMissing[0] != 9 {
+		t.Errorf("expected 9 to be missing, got %v and %v", firstMissing, secondMissing)
+	}
+	if len(second) != 3 || second[0].Name != "A" {
+		t.Errorf("expected cached authors in request order, got %+v", second)
+	}
+	if got := next.batches.Load(); got != 1 {
+		t.Errorf("expected 1 underlying batch call, got %d", got)
+	}
+	if got := next.replicas.Load(); got != 0 {
+		t.Errorf("expected misses to be loaded from the primary, got %d replica reads", got)
+	}
+	if s := repo.Stats(); s.Hits != 4 || s.NegativeHits != 2 || s.Misses != 4 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestAuthorRepository_CreateClearsMiss(t *testing.T) {
+	// Arrange
+	repo, _ := newTestCache(10)
+	ctx := context.Background()
+	repo.GetAuthor(ctx, 1)
+
+	// Act
+	repo.CreateAuthor(ctx, author.CreateAuthorParams{Name: "Ann"})
+	got, err := repo.GetAuthor(ctx, 1)
+
+	// Assert
+	if err != nil || got.Name != "Ann" {
+		t.Errorf("expected created author, got %v, %v", got, err)
+	}
+}
+
+func TestAuthorRepository_WritesInvalidate(t *testing.T) {
+	// Arrange
+	repo, _ := newTestCache(10, &author.Author{Name: "Ann", ExternalID: pgtype.Text{String: "ext-1", Valid: true}})
+	ctx := context.Background()
+	repo.GetAuthor(ctx, 1)
+
+	// Act & Assert
+	if err := repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: 1, Name: "Bea", Bio: pgtype.Text{}}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got, _ := repo.GetAuthor(ctx, 1); got.Name != "Bea" || got.Version != 2 {
+		t.Errorf("expected updated author, got %+v", got)
+	}
+
+	if err := repo.PatchAuthor(ctx, author.PatchAuthorParams{ID: 1, Bio: author.PatchValue("new")}); err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if got, _ := repo.GetAuthor(ctx, 1); got.Bio.String != "new" || got.Version != 3 {
+		t.Errorf("expected patched author, got %+v", got)
+	}
+
+	if err := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: 1}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repo.GetAuthor(ctx, 1); !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected not found after delete, got %v", err)
+	}
+
+	if err := repo.RestoreAuthor(ctx, 1); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if _, err := repo.GetAuthor(ctx, 1); err != nil {
+		t.Errorf("expected author after restore, got %v", err)
+	}
+
+	if _, _, err := repo.UpsertAuthor(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "Cy"}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if got, _ := repo.GetAuthor(ctx, 1); got.Name != "Cy" {
+		t.Errorf("expected upserted author, got %+v", got)
+	}
+}
+
+func TestAuthorRepository_InvalidatesAfterCommit(t *testing.T) {
+	// Arrange
+	mem := memory.NewAuthorRepository()
+	mem.SeedAuthors(&author.Author{Name: "Ann"})
+	repo := NewAuthorRepository(isolatedRepository{Repository: mem}, 10, time.Minute, time.Second)
+	txm := database.NewTxManager(pendingBeginner{}, transaction.Options{}, 0)
+	ctx := context.Background()
+	repo.GetAuthor(ctx, 1)
+
+	// Act
+	var during *author.Author
+	err := txm.RunInTx(ctx, func(txCtx context.Context) error {
+		if err := repo.UpdateAuthor(txCtx, author.UpdateAuthorParams{ID: 1, Name: "Bea"}); err != nil {
+			return err
+		}
+		during, _ = repo.GetAuthor(ctx, 1) // a concurrent read outside the transaction
+		return nil
+	})
+	after, _ := repo.GetAuthor(ctx, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if during.Name != "Ann" {
+		t.Errorf("expected the committed author before the commit, got %+v", during)
+	}
+	if after.Name != "Bea" {
+		t.Errorf("expected the updated author after the commit, got %+v", after)
+	}
+}
+
+func TestAuthorRepository_SeparatesTenants(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(10)
+	owner := tenant.With(context.Background(), "acme")
+	other := tenant.With(context.Background(), "globex")
+	a, _ := repo.CreateAuthor(owner, author.CreateAuthorParams{Name: "Ann"})
+	repo.GetAuthor(owner, a.ID)
+
+	// Act
+	_, err := repo.GetAuthor(other, a.ID)
+
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Fatalf("expected another tenant to miss, got %v", err)
+	}
+	if got := next.gets.Load(); got != 2 {
+		t.Errorf("expected each tenant to reach the repository, got %d calls", got)
+	}
+	if _, err := repo.GetAuthor(owner, a.ID); err != nil {
+		t.Errorf("expected the owner's entry to survive, got %v", err)
+	}
+}
+
+func TestAuthorRepository_Expires(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(10, &author.Author{Name: "Ann"})
+	now := time.Now()
+	repo.now = func() time.Time { return now }
+	ctx := context.Background()
+	repo.GetAuthor(ctx, 1)
+
+	// Act
+	now = now.Add(2 * time.Minute)
+	repo.GetAuthor(ctx, 1)
+
+	// Assert
+	if got := next.gets.Load(); got != 2 {
+		t.Errorf("expected 2 underlying calls, got %d", got)
+	}
+}
+
+func TestAuthorRepository_EvictsLeastRecentlyUsed(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(2, &author.Author{Name: "A"}, &author.Author{Name: "B"}, &author.Author{Name: "C"})
+	ctx := context.Background()
+
+	// Act
+	repo.GetAuthor(ctx, 1)
+	repo.GetAuthor(ctx, 2)
+	repo.GetAuthor(ctx, 1) // 2 is now least recently used
+	repo.GetAuthor(ctx, 3)
+	repo.GetAuthor(ctx, 1)
+
+	// Assert
+	if got := next.gets.Load(); got != 3 {
+		t.Errorf("expected 3 underlying calls, got %d", got)
+	}
+	if s := repo.Stats(); s.Evictions != 1 || s.Entries != 2 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestAuthorRepository_DeduplicatesConcurrentMisses(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(10, &author.Author{Name: "Ann"})
+	next.release = make(chan struct{})
+	ctx := context.Background()
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.GetAuthor(ctx, 1); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	for repo.Stats().Misses < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond) // let the last caller join the flight
+	close(next.release)
+	wg.Wait()
+
+	// Assert
+	if got := next.gets.Load(); got != 1 {
+		t.Errorf("expected 1 underlying call, got %d", got)
+	}
+}
+
+func TestAuthorRepository_CanceledCallerDoesNotFailSharedMiss(t *testing.T) {
+	// Arrange
+	repo, next := newTestCache(10, &author.Author{Name: "Ann"})
+	next.release = make(chan struct{})
+	first, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := repo.GetAuthor(first, 1)
+		firstErr <- err
+	}()
+	for next.gets.Load() < 1 {
+		time.Sleep(time.Millisecond) // the first caller starts the load
+	}
+	type result struct {
+		a   *author.Author
+		err error
+	}
+	second := make(chan result, 1)
+	go func() {
+		a, err := repo.GetAuthor(context.Background(), 1)
+		second <- result{a, err}
+	}()
+	for repo.Stats().Misses < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond) // let the second caller join the flight
+
+	// Act
+	cancel()
+	err := <-firstErr
+	close(next.release)
+	got := <-second
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the first caller to be canceled, got %v", err)
+	}
+	if got.err != nil || got.a.Name != "Ann" {
+		t.Errorf("expected the second caller to get Ann, got %+v, %v", got.a, got.err)
+	}
+	if n := next.gets.Load(); n != 1 {
+		t.Errorf("expected 1 underlying call, got %d", n)
+	}
+}
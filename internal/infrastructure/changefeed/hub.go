@@ -0,0 +1,189 @@
+// Package changefeed fans out live author changes to subscribers, keeping
+// recent changes so that a subscriber can resume after reconnecting.
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+)
+
+// Channel is the PostgreSQL notification channel the authors_notify trigger
+// publishes on.
+const Channel = "author_changes"
+
+// subscriberBuffer is how many changes a subscriber may lag behind before it
+// is dropped.
+const subscriberBuffer = 64
+
+// Hub is an author.ChangeFeed keeping the last changes in a ring buffer.
+// Change IDs have the form "<epoch>-<seq>"; the epoch changes whenever the
+// hub may have missed changes (see Reset), so IDs from an earlier epoch
+// report a gap instead of resuming.
+type Hub struct {
+	mu     sync.Mutex
+	epoch  string
+	epochs uint64 // number of epochs started, keeps epochs distinct
+	seq    uint64
+	buf    []author.Change // ring buffer; buf[seq%len(buf)] holds change seq
+	subs   map[*subscriber]struct{}
+	now    func() time.Time
+}
+
+type subscriber struct {
+	tenant string
+	ch     chan author.Change
+	closed bool
+}
+
+// wants reports whether c belongs to the subscriber's tenant.
+func (s *subscriber) wants(c author.Change) bool {
+	return s.tenant == c.TenantID || s.tenant == tenant.All
+}
+
+// NewHub creates a Hub remembering the last size changes.
+func NewHub(size int) *Hub {
+	h := &Hub{
+		buf:  make([]author.Change, max(size, 1)),
+		subs: make(map[*subscriber]struct{}),
+		now:  time.Now,
+	}
+	h.epoch = h.newEpoch()
+	return h
+}
+
+// newEpoch returns a new epoch, unique across restarts too because it
+// starts with the current time.
+func (h *Hub) newEpoch() string {
+	h.epochs++
+	return strconv.FormatInt(h.now().UnixNano(), 36) + "." + strconv.FormatUint(h.epochs, 10)
+}
+
+// Notify publishes the change encoded in a notification payload.
+func (h *Hub) Notify(payload string) {
+	var c author.Change
+	if err := json.Unmarshal([]byte(payload), &c); err != nil {
+		log.Printf("changefeed: ignoring notification %q: %v", payload, err)
+		return
+	}
+	h.Publish(c)
+}
+
+// Publish assigns c the next ID and delivers it to every subscriber.
+// Subscribers whose buffer is full are dropped.
+func (h *Hub) Publish(c author.Change) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	c.ID = h.epoch + "-" + strconv.FormatUint(h.seq, 10)
+	if c.At.IsZero() {
+		c.At = h.now()
+	}
+	h.buf[h.seq%uint64(len(h.buf))] = c
+
+	for s := range h.subs {
+		if !s.wants(c) {
+			continue
+		}
+		select {
+		case s.ch <- c:
+		default:
+			h.drop(s)
+		}
+	}
+}
+
+// Reset starts a new epoch, forgetting all buffered changes and ending every
+// subscription. Call it when changes may have been missed, e.g. after the
+// notification connection was lost.
+func (h *Hub) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.epoch = h.newEpoch()
+	h.seq = 0
+	clear(h.buf)
+	for s := range h.subs {
+		h.drop(s)
+	}
+}
+
+// Subscribe implements author.ChangeFeed.
+func (h *Hub) Subscribe(ctx context.Context, lastID string) (<-chan author.Change, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := &subscriber{tenant: tenant.FromContext(ctx)}
+	replay, gap := h.since(lastID)
+	s.ch = make(chan author.Change, subscriberBuffer+len(replay))
+	for _, c := range replay {
+		if s.wants(c) {
+			s.ch <- c
+		}
+	}
+	h.subs[s] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.drop(s)
+	}()
+	return s.ch, gap
+}
+
+// since returns the buffered changes after lastID and whether some of them
+// are no longer buffered. The caller must hold h.mu.
+func (h *Hub) since(lastID string) ([]author.Change, bool) {
+	if lastID == "" {
+		return nil, false
+	}
+	epoch, seq, err := parseID(lastID)
+	if err != nil || epoch != h.epoch || seq > h.seq {
+		return nil, true
+	}
+
+	oldest := uint64(1)
+	if h.seq > uint64(len(h.buf)) {
+		oldest = h.seq - uint64(len(h.buf)) + 1
+	}
+	gap := seq+1 < oldest
+	from := max(seq+1, oldest)
+
+	replay := make([]author.Change, 0, h.seq-from+1)
+	for i := from; i <= h.seq; i++ {
+		replay = append(replay, h.buf[i%uint64(len(h.buf))])
+	}
+	return replay, gap
+}
+
+// drop ends a subscription. The caller must hold h.mu.
+func (h *Hub) drop(s *subscriber) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.ch)
+	delete(h.subs, s)
+}
+
+func parseID(id string) (string, uint64, error) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return "", 0, fmt.Errorf("malformed change ID %q", id)
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed change ID %q", id)
+	}
+	return epoch, n, nil
+}
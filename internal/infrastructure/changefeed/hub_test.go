@@ -0,0 +1,167 @@
+package changefeed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+)
+
+// receive drains what is ready on ch without blocking.
+func receive(ch <-chan author.Change) []author.Change {
+	var got []author.Change
+	for {
+		select {
+		case c, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, c)
+		default:
+			return got
+		}
+	}
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	// Arrange
+	hub := NewHub(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, gap := hub.Subscribe(ctx, "")
+
+	// Act
+	hub.Notify(`{"type":"author.created","author_id":7,"version":1}`)
+
+	// Assert
+	if gap {
+		t.Error("expected no gap for a new subscription")
+	}
+	got := receive(ch)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(got))
+	}
+	if c := got[0]; c.Type != author.EventCreated || c.AuthorID != 7 || c.Version != 1 || c.ID == "" || c.At.IsZero() {
+		t.Errorf("unexpected change %+v", c)
+	}
+}
+
+func TestHub_SubscribeResumesAfterLastID(t *testing.T) {
+	// Arrange
+	hub := NewHub(10)
+	ctx := context.Background()
+	first, _ := hub.Subscribe(ctx, "")
+	for id := int64(1); id <= 3; id++ {
+		hub.Publish(author.Change{Type: author.EventUpdated, AuthorID: id})
+	}
+	seen := receive(first)
+
+	// Act
+	ch, gap := hub.Subscribe(ctx, seen[0].ID)
+
+	// Assert
+	if gap {
+		t.Error("expected no gap")
+	}
+	got := receive(ch)
+	if len(got) != 2 || got[0].AuthorID != 2 || got[1].AuthorID != 3 {
+		t.Errorf("expected changes 2 and 3 to be replayed, got %+v", got)
+	}
+}
+
+func TestHub_SubscribeReportsGap(t *testing.T) {
+	hub := NewHub(2)
+	ctx := context.Background()
+	first, _ := hub.Subscribe(ctx, "")
+	for id := int64(1); id <= 4; id++ {
+		hub.Publish(author.Change{AuthorID: id})
+	}
+	seen := receive(first)
+
+	tests := []struct {
+		name       string
+		lastID     string
+		wantGap    bool
+		wantReplay int
+	}{
+		{"evicted", seen[0].ID, true, 2},
+		{"buffered", seen[1].ID, false, 2},
+		{"latest", seen[3].ID, false, 0},
+		{"malformed", "nope", true, 0},
+		{"other epoch", "x.1-1", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch, gap := hub.Subscribe(ctx, tt.lastID)
+			if got := receive(ch); gap != tt.wantGap || len(got) != tt.wantReplay {
+				t.Errorf("got gap %v and %d changes, want %v and %d", gap, len(got), tt.wantGap, tt.wantReplay)
+			}
+		})
+	}
+}
+
+func TestHub_SubscribeFiltersByTenant(t *testing.T) {
+	// Arrange
+	hub := NewHub(10)
+	ctx := context.Background()
+	hub.Publish(author.Change{AuthorID: 1, TenantID: "acme"})
+	hub.Publish(author.Change{AuthorID: 2, TenantID: "globex"})
+	all, _ := hub.Subscribe(tenant.With(ctx, tenant.All), "")
+
+	// Act
+	ch, _ := hub.Subscribe(tenant.With(ctx, "acme"), "")
+	hub.Publish(author.Change{AuthorID: 3, TenantID: "globex"})
+	hub.Publish(author.Change{AuthorID: 4, TenantID: "acme"})
+	replayed, _ := hub.Subscribe(tenant.With(ctx, "globex"), hub.epoch+"-0")
+
+	// Assert
+	if got := receive(all); len(got) != 2 {
+		t.Errorf("expected tenant.All to see every change, got %+v", got)
+	}
+	if got := receive(ch); len(got) != 1 || got[0].AuthorID != 4 {
+		t.Errorf("expected only acme's change, got %+v", got)
+	}
+	if got := receive(replayed); len(got) != 2 || got[0].AuthorID != 2 || got[1].AuthorID != 3 {
+		t.Errorf("expected only globex's changes to be replayed, got %+v", got)
+	}
+}
+
+func TestHub_ResetEndsSubscriptions(t *testing.T) {
+	// Arrange
+	hub := NewHub(10)
+	ctx := context.Background()
+	ch, _ := hub.Subscribe(ctx, "")
+	hub.Publish(author.Change{AuthorID: 1})
+	last := receive(ch)[0].ID
+
+	// Act
+	hub.Reset()
+
+	// Assert
+	if _, ok := <-ch; ok {
+		t.Error("expected subscription to be closed")
+	}
+	if _, gap := hub.Subscribe(ctx, last); !gap {
+		t.Error("expected a gap when resuming from before the reset")
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	// Arrange
+	hub := NewHub(10)
+	ch, _ := hub.Subscribe(context.Background(), "")
+
+	// Act
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(author.Change{AuthorID: int64(i)})
+	}
+
+	// Assert
+	if got := receive(ch); len(got) != subscriberBuffer {
+		t.Errorf("expected %d buffered changes before the drop, got %d", subscriberBuffer, len(got))
+	}
+	if _, ok := <-ch; ok {
+		t.Error("expected slow subscription to be closed")
+	}
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Reconnect backoff for Listener: it doubles after every failed attempt.
+const (
+	listenBackoff    = 500 * time.Millisecond
+	maxListenBackoff = 30 * time.Second
+)
+
+// Listener receives PostgreSQL notifications on one channel over a
+// dedicated connection taken from the pool, reconnecting whenever it is
+// lost.
+type Listener struct {
+	pool        *pgxpool.Pool
+	channel     string
+	onNotify    func(payload string)
+	onReconnect func()
+}
+
+// NewListener creates a Listener calling onNotify with the payload of every
+// notification on channel. Notifications sent while the connection is down
+// are lost; onReconnect, if not nil, is called after every reconnect so the
+// caller can account for them.
+func NewListener(pool *pgxpool.Pool, channel string, onNotify func(payload string), onReconnect func()) *Listener {
+	return &Listener{
+		pool:        pool,
+		channel:     channel,
+		onNotify:    onNotify,
+		onReconnect: onReconnect,
+	}
+}
+
+// Run listens until ctx is done.
+func (l *Listener) Run(ctx context.Context) {
+	backoff := listenBackoff
+	connected := false
+	for {
+		err := l.listen(ctx, func() {
+			if connected && l.onReconnect != nil {
+				l.onReconnect()
+			}
+			connected = true
+			backoff = listenBackoff
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("listen on %s: %v; reconnecting in %s", l.channel, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxListenBackoff)
+	}
+}
+
+// listen runs one LISTEN session, calling ready once it is established. The
+// connection is closed rather than returned to the pool afterwards, so no
+// other user inherits the LISTEN.
+func (l *Listener) listen(ctx context.Context, ready func()) error {
+	pooled, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	ready()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		l.onNotify(n.Payload)
+	}
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/migrate"
+	"github.com/seldomhappy/sqlc-test/migrations"
+)
+
+// TestListener is skipped unless TEST_DATABASE_URL points at a disposable
+// database: it inserts authors and terminates the listening connection.
+func TestListener(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrate.New(pool, all).Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	payloads := make(chan string, 10)
+	reconnects := make(chan struct{}, 1)
+	l := NewListener(pool, "author_changes",
+		func(payload string) { payloads <- payload },
+		func() { reconnects <- struct{}{} })
+	go l.Run(ctx)
+
+	// Insert until the listener is up and sees the notification.
+	await := func() string {
+		t.Helper()
+		for {
+			if _, err := pool.Exec(ctx, "SELECT set_config('app.tenant_id', 'listener', true); INSERT INTO authors (name) VALUES ('listener test')"); err != nil {
+				t.Fatalf("insert: %v", err)
+			}
+			select {
+			case p := <-payloads:
+				return p
+			case <-time.After(100 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("no notification received")
+			}
+		}
+	}
+	if p := await(); p == "" {
+		t.Error("expected a payload")
+	}
+
+	// A lost connection is re-established and reported.
+	if _, err := pool.Exec(ctx, "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE query LIKE 'LISTEN %'"); err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	select {
+	case <-reconnects:
+	case <-ctx.Done():
+		t.Fatal("listener did not reconnect")
+	}
+	await()
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/seldomhappy/sqlc-test/config"
+	"github.com/seldomhappy/sqlc-test/mysqldb"
+)
+
+// MySQLDB wraps a database/sql handle to a MySQL database.
+type MySQLDB struct {
+	db *sql.DB
+}
+
+// NewMySQL connects to the MySQL database named by cfg.DatabaseURL (a
+// go-sql-driver DSN), verifies it responds and applies mysqldb.Upgrades and
+// then mysqldb.Schema, so tables created by an older schema keep their rows.
+// parseTime is always enabled and times are read and written in UTC.
+func NewMySQL(ctx context.Context, cfg *config.Config) (*MySQLDB, error) {
+	dsn, err := mysql.ParseDSN(cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+	dsn.ParseTime = true
+	dsn.MultiStatements = true
+
+	db, err := sql.Open("mysql", dsn.FormatDSN())
+	if err != nil {
+		return nil, fmt.Errorf("open mysql database: %w", err)
+	}
+	if cfg.DBMaxConns > 0 {
+		db.SetMaxOpenConns(int(cfg.DBMaxConns))
+	}
+	if cfg.DBMinConns > 0 {
+		db.SetMaxIdleConns(int(cfg.DBMinConns))
+	}
+	if cfg.DBMaxConnIdleTime > 0 {
+		db.SetConnMaxIdleTime(cfg.DBMaxConnIdleTime)
+	}
+	if cfg.DBMaxConnLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.DBMaxConnLifetime)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	if err := upgradeMySQL(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("upgrade mysql schema: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, mysqldb.Schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("apply mysql schema: %w", err)
+	}
+	return &MySQLDB{db: db}, nil
+}
+
+// upgradeMySQL runs each of mysqldb.Upgrades whose table exists in the
+// current database but lacks its column. A missing table is left to
+// mysqldb.Schema.
+func upgradeMySQL(ctx context.Context, db *sql.DB) error {
+	for _, u := range mysqldb.Upgrades {
+		var columns, found int
+		err := db.QueryRowContext(ctx,
+			`SELECT count(*), coalesce(sum(column_name = ?), 0)
+			   FROM information_schema.columns
+			  WHERE table_schema = DATABASE() AND table_name = ?`,
+			u.Column, u.Table).Scan(&columns, &found)
+		if err != nil {
+			return fmt.Errorf("inspect %s: %w", u.Table, err)
+		}
+		if columns == 0 || found > 0 {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, u.SQL); err != nil {
+			return fmt.Errorf("add %s.%s: %w", u.Table, u.Column, err)
+		}
+	}
+	return nil
+}
+
+// GetDB returns the underlying *sql.DB.
+func (db *MySQLDB) GetDB() *sql.DB {
+	return db.db
+}
+
+// Ping checks that the database responds.
+func (db *MySQLDB) Ping(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+// Close closes the database handle.
+func (db *MySQLDB) Close() {
+	db.db.Close()
+}
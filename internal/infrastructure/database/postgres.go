@@ -2,26 +2,122 @@ package database
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/seldomhappy/sqlc-test/config"
 )
 
-// PostgresDB wraps a pgx.Conn for database operations.
+// PostgresDB wraps a pgxpool.Pool for database operations, plus pools for
+// any read replicas configured in cfg.DatabaseReplicaURLs. Queries on all
+// pools are traced by a single QueryTracer.
 type PostgresDB struct {
-	conn *pgx.Conn
+	pool         *pgxpool.Pool
+	replicas     *ReplicaSet
+	stopReplicas context.CancelFunc
+	tracer       *QueryTracer
 }
 
-// New creates a new PostgresDB instance.
-func New(conn *pgx.Conn) *PostgresDB {
-	return &PostgresDB{conn: conn}
+// New creates a new PostgresDB instance backed by a connection pool
+// configured from cfg. It verifies connectivity to the primary before
+// returning; unreachable replicas are only marked unhealthy.
+func New(ctx context.Context, cfg *config.Config) (*PostgresDB, error) {
+	tracer := NewQueryTracer(cfg.DBSlowQueryThreshold)
+	pool, err := newPool(ctx, cfg.DatabaseURL, cfg, tracer)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	replicaPools := make([]*pgxpool.Pool, 0, len(cfg.DatabaseReplicaURLs))
+	for i, url := range cfg.DatabaseReplicaURLs {
+		replica, err := newPool(ctx, url, cfg, tracer)
+		if err != nil {
+			for _, p := range replicaPools {
+				p.Close()
+			}
+			pool.Close()
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+		replicaPools = append(replicaPools, replica)
+	}
+	replicas := NewReplicaSet(pool, replicaPools...)
+	replicas.CheckHealth(ctx)
+
+	runCtx, stop := context.WithCancel(context.Background())
+	if len(replicaPools) > 0 && cfg.DBReplicaCheckPeriod > 0 {
+		go replicas.Run(runCtx, cfg.DBReplicaCheckPeriod)
+	}
+	return &PostgresDB{pool: pool, replicas: replicas, stopReplicas: stop, tracer: tracer}, nil
+}
+
+// newPool creates a connection pool for url using the pool settings in cfg
+// and tracing queries with tracer.
+func newPool(ctx context.Context, url string, cfg *config.Config, tracer *QueryTracer) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+	poolCfg.ConnConfig.Tracer = tracer
+	if cfg.DBMaxConns > 0 {
+		poolCfg.MaxConns = cfg.DBMaxConns
+	}
+	if cfg.DBMinConns > 0 {
+		poolCfg.MinConns = cfg.DBMinConns
+	}
+	if cfg.DBMaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.DBMaxConnIdleTime
+	}
+	if cfg.DBMaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.DBMaxConnLifetime
+	}
+	if cfg.DBHealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.DBHealthCheckPeriod
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create connection pool: %w", err)
+	}
+	return pool, nil
+}
+
+// GetPool returns the underlying pgxpool.Pool of the primary.
+func (db *PostgresDB) GetPool() *pgxpool.Pool {
+	return db.pool
+}
+
+// Replicas returns the read replicas. With none configured every read is
+// routed to the primary.
+func (db *PostgresDB) Replicas() *ReplicaSet {
+	return db.replicas
+}
+
+// QueryStats returns the metrics of every query run so far, keyed by query
+// name.
+func (db *PostgresDB) QueryStats() map[string]QueryStats {
+	return db.tracer.Stats()
+}
+
+// BypassesRLS reports whether the primary's role skips row-level security
+// (it is a superuser or has BYPASSRLS), which disables tenant isolation.
+func (db *PostgresDB) BypassesRLS(ctx context.Context) (bool, error) {
+	var bypass bool
+	err := db.pool.QueryRow(ctx, "SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypass)
+	return bypass, err
 }
 
-// GetConn returns the underlying pgx.Conn.
-func (db *PostgresDB) GetConn() *pgx.Conn {
-	return db.conn
+// Ping checks that a connection can be acquired and the primary responds.
+func (db *PostgresDB) Ping(ctx context.Context) error {
+	return db.pool.Ping(ctx)
 }
 
-// Close closes the database connection.
-func (db *PostgresDB) Close(ctx context.Context) error {
-	return db.conn.Close(ctx)
+// Close closes all connections in the primary and replica pools.
+func (db *PostgresDB) Close() {
+	db.stopReplicas()
+	db.replicas.close()
+	db.pool.Close()
 }
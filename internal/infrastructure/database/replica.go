@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replicaPingTimeout bounds each replica ping made by CheckHealth.
+const replicaPingTimeout = 2 * time.Second
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads are served by the primary even
+// when replicas are configured. Use it after a write whose result must be
+// visible to the reads that follow, since replicas may lag behind.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryRequested reports whether ctx was marked by WithPrimary.
+func PrimaryRequested(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryKey{}).(bool)
+	return forced
+}
+
+// ReplicaSet routes read-only queries across read replicas of a primary.
+// Replicas are used round-robin while healthy; reads fall back to the
+// primary when none is healthy or the caller asks for it with WithPrimary.
+type ReplicaSet struct {
+	primary  *pgxpool.Pool
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// NewReplicaSet creates a ReplicaSet in front of primary. All replicas start
+// out healthy; call CheckHealth or Run to keep their state current.
+func NewReplicaSet(primary *pgxpool.Pool, replicas ...*pgxpool.Pool) *ReplicaSet {
+	s := &ReplicaSet{primary: primary}
+	for _, pool := range replicas {
+		r := &replica{pool: pool}
+		r.healthy.Store(true)
+		s.replicas = append(s.replicas, r)
+	}
+	return s
+}
+
+// Reader returns the pool a read outside a transaction should use: the next
+// healthy replica, or the primary if ctx was marked by WithPrimary or no
+// replica is healthy.
+func (s *ReplicaSet) Reader(ctx context.Context) *pgxpool.Pool {
+	if len(s.replicas) == 0 || PrimaryRequested(ctx) {
+		return s.primary
+	}
+	start := s.next.Add(1)
+	for i := range s.replicas {
+		r := s.replicas[(start+uint64(i))%uint64(len(s.replicas))]
+		if r.healthy.Load() {
+			return r.pool
+		}
+	}
+	return s.primary
+}
+
+// CheckHealth pings every replica and records which ones responded.
+func (s *ReplicaSet) CheckHealth(ctx context.Context) {
+	for _, r := range s.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		r.healthy.Store(r.pool.Ping(pingCtx) == nil)
+		cancel()
+	}
+}
+
+// Run calls CheckHealth every period until ctx is done.
+func (s *ReplicaSet) Run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckHealth(ctx)
+		}
+	}
+}
+
+// close closes the replica pools; the primary is owned by the caller.
+func (s *ReplicaSet) close() {
+	for _, r := range s.replicas {
+		r.pool.Close()
+	}
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newLazyPool returns a pool that never connects unless used. Port 1 refuses
+// connections, so pings fail fast.
+func newLazyPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "host=127.0.0.1 port=1 connect_timeout=1")
+	if err != nil {
+		t.Fatalf("create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestReplicaSet_ReaderRoundRobin(t *testing.T) {
+	// Arrange
+	primary, r1, r2 := newLazyPool(t), newLazyPool(t), newLazyPool(t)
+	s := NewReplicaSet(primary, r1, r2)
+	ctx := context.Background()
+
+	// Act
+	got := []*pgxpool.Pool{s.Reader(ctx), s.Reader(ctx), s.Reader(ctx), s.Reader(ctx)}
+
+	// Assert
+	if got[0] == got[1] || got[0] != got[2] || got[1] != got[3] {
+		t.Errorf("expected reads to alternate between replicas")
+	}
+	for _, p := range got {
+		if p == primary {
+			t.Errorf("expected no reads on the primary")
+		}
+	}
+}
+
+func TestReplicaSet_ReaderSkipsUnhealthyReplicas(t *testing.T) {
+	// Arrange
+	primary, r1, r2 := newLazyPool(t), newLazyPool(t), newLazyPool(t)
+	s := NewReplicaSet(primary, r1, r2)
+	s.replicas[0].healthy.Store(false)
+	ctx := context.Background()
+
+	// Act & Assert
+	for i := 0; i < 3; i++ {
+		if got := s.Reader(ctx); got != r2 {
+			t.Fatalf("read %d: expected the healthy replica", i)
+		}
+	}
+}
+
+func TestReplicaSet_ReaderFallsBackToPrimary(t *testing.T) {
+	// Arrange
+	primary, r1 := newLazyPool(t), newLazyPool(t)
+	s := NewReplicaSet(primary, r1)
+
+	// Act
+	s.CheckHealth(context.Background())
+
+	// Assert
+	if s.replicas[0].healthy.Load() {
+		t.Fatal("expected unreachable replica to be unhealthy")
+	}
+	if got := s.Reader(context.Background()); got != primary {
+		t.Error("expected the primary when no replica is healthy")
+	}
+}
+
+func TestReplicaSet_WithPrimary(t *testing.T) {
+	// Arrange
+	primary, r1 := newLazyPool(t), newLazyPool(t)
+	s := NewReplicaSet(primary, r1)
+
+	// Act
+	got := s.Reader(WithPrimary(context.Background()))
+
+	// Assert
+	if got != primary {
+		t.Error("expected WithPrimary to force the primary")
+	}
+}
+
+func TestReplicaSet_NoReplicas(t *testing.T) {
+	// Arrange
+	primary := newLazyPool(t)
+	s := NewReplicaSet(primary)
+
+	// Act
+	got := s.Reader(context.Background())
+
+	// Assert
+	if got != primary {
+		t.Error("expected the primary without replicas")
+	}
+}
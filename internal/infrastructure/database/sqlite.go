@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/seldomhappy/sqlc-test/config"
+	"github.com/seldomhappy/sqlc-test/sqlitedb"
+
+	// Registers the pure-Go "sqlite" database/sql driver.
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteDB wraps a database/sql handle to a SQLite database file.
+type SQLiteDB struct {
+	db *sql.DB
+}
+
+// NewSQLite opens the SQLite database named by cfg.DatabaseURL, verifies it
+// responds and applies sqlitedb.Upgrades and then sqlitedb.Schema, so files
+// created by an older schema keep their rows. The DSN should enable foreign_keys
+// and a busy_timeout through _pragma parameters, as the default does.
+func NewSQLite(ctx context.Context, cfg *config.Config) (*SQLiteDB, error) {
+	db, err := sql.Open("sqlite", cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite database: %w", err)
+	}
+	if cfg.DBMaxConns > 0 {
+		db.SetMaxOpenConns(int(cfg.DBMaxConns))
+	}
+	if cfg.DBMaxConnIdleTime > 0 {
+		db.SetConnMaxIdleTime(cfg.DBMaxConnIdleTime)
+	}
+	if cfg.DBMaxConnLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.DBMaxConnLifetime)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	if err := upgradeSQLite(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("upgrade sqlite schema: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, sqlitedb.Schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("apply sqlite schema: %w", err)
+	}
+	return &SQLiteDB{db: db}, nil
+}
+
+// upgradeSQLite runs each of sqlitedb.Upgrades whose table exists but lacks
+// its column. A missing table is left to sqlitedb.Schema.
+func upgradeSQLite(ctx context.Context, db *sql.DB) error {
+	for _, u := range sqlitedb.Upgrades {
+		var columns, found int
+		err := db.QueryRowContext(ctx,
+			"SELECT count(*), count(*) FILTER (WHERE name = ?) FROM pragma_table_info(?)",
+			u.Column, u.Table).Scan(&columns, &found)
+		if err != nil {
+			return fmt.Errorf("inspect %s: %w", u.Table, err)
+		}
+		if columns == 0 || found > 0 {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, u.SQL); err != nil {
+			return fmt.Errorf("add %s.%s: %w", u.Table, u.Column, err)
+		}
+	}
+	return nil
+}
+
+// GetDB returns the underlying *sql.DB.
+func (db *SQLiteDB) GetDB() *sql.DB {
+	return db.db
+}
+
+// Ping checks that the database responds.
+func (db *SQLiteDB) Ping(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+// Close closes the database handle.
+func (db *SQLiteDB) Close() {
+	db.db.Close()
+}
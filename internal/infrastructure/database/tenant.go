@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+)
+
+// Beginner starts transactions. It is satisfied by *pgxpool.Pool, *pgx.Conn
+// and pgx.Tx (as a savepoint).
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// InTenant runs fn in a transaction scoped to the tenant of ctx (see
+// tenant.FromContext) by setting app.tenant_id for that transaction only,
+// which the row-level security policies read. If ctx carries a transaction
+// fn joins it; otherwise one is begun on db and committed if fn succeeds.
+// Without a tenant nothing is set, so the policies hide every row.
+func InTenant(ctx context.Context, db Beginner, fn func(tx pgx.Tx) error) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		if err := setTenant(ctx, tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// setTenant is SET LOCAL app.tenant_id, parameterized.
+func setTenant(ctx context.Context, tx pgx.Tx) error {
+	id := tenant.FromContext(ctx)
+	if id == "" {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "SELECT set_config('app.tenant_id', $1, true)", id); err != nil {
+		return fmt.Errorf("set tenant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// latencyBuckets are the upper bounds of the QueryTracer latency histogram.
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// QueryStats is a snapshot of the metrics of one query.
+type QueryStats struct {
+	Calls  int64 `json:"calls"`
+	Errors int64 `json:"errors"`
+	Rows   int64 `json:"rows"`
+	// TotalMillis is the time spent in all calls.
+	TotalMillis float64 `json:"total_ms"`
+	// Latency is a cumulative histogram: the number of calls that took at
+	// most LE, with a final "+Inf" bucket counting all calls.
+	Latency []Bucket `json:"latency"`
+}
+
+// Bucket is one QueryStats latency bucket.
+type Bucket struct {
+	LE    string `json:"le"`
+	Count int64  `json:"count"`
+}
+
+// QueryTracer is a pgx tracer recording latency, row counts and errors per
+// query and logging queries slower than a threshold. Queries generated by
+// sqlc are named after their "-- name:" annotation; any other statement is
+// named after its first keyword (e.g. "BEGIN").
+type QueryTracer struct {
+	slow time.Duration
+	logf func(format string, args ...any)
+	now  func() time.Time
+
+	mu      sync.Mutex
+	queries map[string]*queryMetrics
+}
+
+type queryMetrics struct {
+	calls, errors, rows int64
+	total               time.Duration
+	buckets             []int64 // buckets[i] counts calls within latencyBuckets[i]
+}
+
+// traceKey is the context key for the traceData of a running query.
+type traceKey struct{}
+
+type traceData struct {
+	name  string
+	args  []any
+	start time.Time
+}
+
+// NewQueryTracer creates a QueryTracer logging queries taking longer than
+// slow; 0 disables the slow-query log.
+func NewQueryTracer(slow time.Duration) *QueryTracer {
+	return &QueryTracer{
+		slow:    slow,
+		logf:    log.Printf,
+		now:     time.Now,
+		queries: make(map[string]*queryMetrics),
+	}
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, traceKey{}, &traceData{name: queryName(data.SQL), args: data.Args, start: t.now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.end(ctx, data.CommandTag, data.Err)
+}
+
+func (t *QueryTracer) end(ctx context.Context, tag pgconn.CommandTag, err error) {
+	data, ok := ctx.Value(traceKey{}).(*traceData)
+	if !ok {
+		return
+	}
+	elapsed := t.now().Sub(data.start)
+
+	t.mu.Lock()
+	m := t.queries[data.name]
+	if m == nil {
+		m = &queryMetrics{buckets: make([]int64, len(latencyBuckets))}
+		t.queries[data.name] = m
+	}
+	m.calls++
+	m.total += elapsed
+	if err != nil {
+		m.errors++
+	} else {
+		m.rows += tag.RowsAffected()
+	}
+	for i, le := range latencyBuckets {
+		if elapsed <= le {
+			m.buckets[i]++
+		}
+	}
+	t.mu.Unlock()
+
+	if t.slow > 0 && elapsed > t.slow {
+		t.logf("slow query %s took %s (args: %s, error: %v)", data.name, elapsed, redact(data.args), err)
+	}
+}
+
+// Stats returns a snapshot of the metrics of every query seen so far.
+func (t *QueryTracer) Stats() map[string]QueryStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make(map[string]QueryStats, len(t.queries))
+	for name, m := range t.queries {
+		latency := make([]Bucket, 0, len(latencyBuckets)+1)
+		for i, le := range latencyBuckets {
+			latency = append(latency, Bucket{LE: le.String(), Count: m.buckets[i]})
+		}
+		latency = append(latency, Bucket{LE: "+Inf", Count: m.calls})
+		stats[name] = QueryStats{
+			Calls:       m.calls,
+			Errors:      m.errors,
+			Rows:        m.rows,
+			TotalMillis: float64(m.total) / float64(time.Millisecond),
+			Latency:     latency,
+		}
+	}
+	return stats
+}
+
+// queryName returns the sqlc name of sql, or its first keyword.
+func queryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(sql, "-- name:"); ok {
+		if fields := strings.Fields(rest); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return "unknown"
+}
+
+// redact describes args by type only, so logs never contain user data.
+func redact(args []any) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = fmt.Sprintf("$%d=%T", i+1, arg)
+	}
+	return "[" + strings.Join(types, " ") + "]"
+}
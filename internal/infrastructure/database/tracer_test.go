@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// runQuery traces one query taking elapsed.
+func runQuery(tr *QueryTracer, now *time.Time, sql string, args []any, elapsed time.Duration, tag string, err error) {
+	ctx := tr.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: args})
+	*now = now.Add(elapsed)
+	tr.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag(tag), Err: err})
+}
+
+func TestQueryTracer_Stats(t *testing.T) {
+	// Arrange
+	tr := NewQueryTracer(0)
+	now := time.Now()
+	tr.now = func() time.Time { return now }
+	const listAuthors = "-- name: ListAuthors :many\nSELECT id, name FROM authors"
+
+	// Act
+	runQuery(tr, &now, listAuthors, nil, 3*time.Millisecond, "SELECT 2", nil)
+	runQuery(tr, &now, listAuthors, nil, 30*time.Millisecond, "", errors.New("boom"))
+	runQuery(tr, &now, "begin", nil, time.Millisecond, "BEGIN", nil)
+
+	// Assert
+	stats := tr.Stats()
+	list := stats["ListAuthors"]
+	if list.Calls != 2 || list.Errors != 1 || list.Rows != 2 || list.TotalMillis != 33 {
+		t.Errorf("unexpected ListAuthors stats %+v", list)
+	}
+	latency := fmt.Sprint(list.Latency)
+	if want := "[{1ms 0} {5ms 1} {10ms 1} {25ms 1} {50ms 2} {100ms 2} {250ms 2} {500ms 2} {1s 2} {5s 2} {+Inf 2}]"; latency != want {
+		t.Errorf("unexpected latency histogram %s", latency)
+	}
+	if stats["BEGIN"].Calls != 1 {
+		t.Errorf("expected BEGIN to be counted, got %+v", stats)
+	}
+}
+
+func TestQueryTracer_LogsSlowQueriesRedacted(t *testing.T) {
+	// Arrange
+	tr := NewQueryTracer(100 * time.Millisecond)
+	now := time.Now()
+	tr.now = func() time.Time { return now }
+	var logged []string
+	tr.logf = func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) }
+	const getAuthor = "-- name: GetAuthor :one\nSELECT * FROM authors WHERE name = $1 AND id = $2"
+
+	// Act
+	runQuery(tr, &now, getAuthor, []any{"secret", int64(1)}, 50*time.Millisecond, "SELECT 1", nil)
+	runQuery(tr, &now, getAuthor, []any{"secret", int64(1)}, 150*time.Millisecond, "SELECT 1", nil)
+
+	// Assert
+	if len(logged) != 1 {
+		t.Fatalf("expected 1 slow query log, got %q", logged)
+	}
+	if !strings.Contains(logged[0], "GetAuthor took 150ms") || !strings.Contains(logged[0], "$1=string $2=int64") {
+		t.Errorf("unexpected log %q", logged[0])
+	}
+	if strings.Contains(logged[0], "secret") {
+		t.Errorf("expected arguments to be redacted, got %q", logged[0])
+	}
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+)
+
+// serializationFailure is the SQLSTATE reported when a transaction cannot be
+// serialized with concurrent transactions and should be retried.
+const serializationFailure = "40001"
+
+// retryBackoff is the base delay between retries; it doubles on each attempt.
+const retryBackoff = 10 * time.Millisecond
+
+type txKey struct{}
+
+type afterCommitKey struct{}
+
+// afterCommitHooks collects the functions AfterCommit registers for one
+// transaction attempt.
+type afterCommitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// TxBeginner starts transactions. It is satisfied by *pgxpool.Pool and *pgx.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// TxManager implements transaction.Manager on top of pgx. The active pgx.Tx
+// is stored in the context handed to the callback, so repositories built on
+// TxFromContext transparently join it.
+type TxManager struct {
+	db         TxBeginner
+	defaults   transaction.Options
+	maxRetries int
+}
+
+// NewTxManager creates a new TxManager. Transactions failing with a
+// serialization error are retried up to maxRetries times.
+func NewTxManager(db TxBeginner, defaults transaction.Options, maxRetries int) *TxManager {
+	return &TxManager{
+		db:         db,
+		defaults:   defaults,
+		maxRetries: maxRetries,
+	}
+}
+
+// TxFromContext returns the transaction stored in ctx by TxManager, if any.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// AfterCommit runs fn once the transaction TxManager stored in ctx has
+// committed, or right away if ctx carries none. fn is dropped if the
+// transaction rolls back. Use it for side effects that must not be seen
+// before the change is, such as invalidating a cache.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks)
+	if !ok {
+		fn()
+		return
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+}
+
+// RunInTx runs fn in a transaction using the manager's default options.
+func (m *TxManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.RunInTxWithOptions(ctx, m.defaults, fn)
+}
+
+// RunInTxWithOptions runs fn in a transaction using opts. If ctx already
+// carries a transaction, fn joins it and opts are ignored; the outermost
+// call owns commit, rollback and retries.
+func (m *TxManager) RunInTxWithOptions(ctx context.Context, opts transaction.Options, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	txOpts := pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(opts.IsoLevel)}
+	if opts.ReadOnly {
+		txOpts.AccessMode = pgx.ReadOnly
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = m.runOnce(ctx, txOpts, fn)
+		if err == nil || !isSerializationFailure(err) || attempt >= m.maxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryBackoff << attempt):
+		}
+	}
+}
+
+// runOnce executes a single transaction attempt.
+func (m *TxManager) runOnce(ctx context.Context, txOpts pgx.TxOptions, fn func(ctx context.Context) error) error {
+	tx, err := m.db.BeginTx(ctx, txOpts)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	hooks := &afterCommitHooks{}
+	txCtx := context.WithValue(context.WithValue(ctx, txKey{}, tx), afterCommitKey{}, hooks)
+	if err := fn(txCtx); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("rollback transaction: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	hooks.mu.Lock()
+	fns := hooks.fns
+	hooks.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+	return nil
+}
+
+// isSerializationFailure reports whether err is a PostgreSQL serialization failure.
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == serializationFailure
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+)
+
+type fakeTx struct {
+	pgx.Tx
+	committed  bool
+	rolledBack bool
+}
+
+func (f *fakeTx) Commit(ctx context.Context) error {
+	f.committed = true
+	return nil
+}
+
+func (f *fakeTx) Rollback(ctx context.Context) error {
+	f.rolledBack = true
+	return nil
+}
+
+type fakeBeginner struct {
+	txs  []*fakeTx
+	opts []pgx.TxOptions
+}
+
+func (f *fakeBeginner) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	f.txs = append(f.txs, tx)
+	f.opts = append(f.opts, opts)
+	return tx, nil
+}
+
+func TestTxManager_RunInTxCommits(t *testing.T) {
+	// Arrange
+	db := &fakeBeginner{}
+	m := NewTxManager(db, transaction.Options{IsoLevel: transaction.Serializable}, 0)
+
+	// Act
+	err := m.RunInTx(context.Background(), func(ctx context.Context) error {
+		if _, ok := TxFromContext(ctx); !ok {
+			t.Error("expected transaction in context")
+		}
+		return nil
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(db.txs) != 1 || !db.txs[0].committed {
+		t.Errorf("expected one committed transaction")
+	}
+	if db.opts[0].IsoLevel != pgx.Serializable {
+		t.Errorf("expected serializable isolation, got %q", db.opts[0].IsoLevel)
+	}
+}
+
+func TestTxManager_RunInTxRollsBackOnError(t *testing.T) {
+	// Arrange
+	db := &fakeBeginner{}
+	m := NewTxManager(db, transaction.Options{}, 0)
+	want := errors.New("boom")
+
+	// Act
+	err := m.RunInTxWithOptions(context.Background(), transaction.Options{ReadOnly: true}, func(ctx context.Context) error {
+		return want
+	})
+
+	// Assert
+	if !errors.Is(err, want) {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+	if !db.txs[0].rolledBack || db.txs[0].committed {
+		t.Errorf("expected rollback without commit")
+	}
+	if db.opts[0].AccessMode != pgx.ReadOnly {
+		t.Errorf("expected read only access mode, got %q", db.opts[0].AccessMode)
+	}
+}
+
+func TestTxManager_RetriesSerializationFailure(t *testing.T) {
+	// Arrange
+	db := &fakeBeginner{}
+	m := NewTxManager(db, transaction.Options{}, 2)
+	calls := 0
+
+	// Act
+	err := m.RunInTx(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestTxManager_NestedCallJoinsOuterTransaction(t *testing.T) {
+	// Arrange
+	db := &fakeBeginner{}
+	m := NewTxManager(db, transaction.Options{}, 0)
+
+	// Act
+	err := m.RunInTx(context.Background(), func(ctx context.Context) error {
+		return m.RunInTx(ctx, func(ctx context.Context) error { return nil })
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(db.txs) != 1 {
+		t.Errorf("expected 1 transaction, got %d", len(db.txs))
+	}
+}
+
+func TestTxManager_AfterCommitRunsOnlyOnCommit(t *testing.T) {
+	// Arrange
+	db := &fakeBeginner{}
+	m := NewTxManager(db, transaction.Options{}, 0)
+	var ran []string
+
+	// Act
+	m.RunInTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = append(ran, "rolled back") })
+		return errors.New("boom")
+	})
+	m.RunInTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() {
+			if !db.txs[1].committed {
+				t.Error("expected the hook to run after commit")
+			}
+			ran = append(ran, "committed")
+		})
+		if len(ran) != 0 {
+			t.Error("expected the hook to wait for the commit")
+		}
+		return nil
+	})
+	AfterCommit(context.Background(), func() { ran = append(ran, "no transaction") })
+
+	// Assert
+	if len(ran) != 2 || ran[0] != "committed" || ran[1] != "no transaction" {
+		t.Errorf("unexpected hook runs %v", ran)
+	}
+}
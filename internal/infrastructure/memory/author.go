@@ -0,0 +1,375 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// AuthorRepository implements the author.Repository interface in memory.
+// Names are compared byte-wise rather than with a database collation, and
+// full-text search matches whole words case-insensitively without stemming.
+type AuthorRepository struct {
+	s *Store
+}
+
+// NewAuthorRepository creates an AuthorRepository backed by a fresh Store.
+func NewAuthorRepository() *AuthorRepository {
+	return NewStore().Authors()
+}
+
+// SeedAuthors stores copies of authors as-is, owned by no tenant. Authors
+// without an ID are assigned the next one and a zero Version becomes 1, as on
+// insert.
+func (r *AuthorRepository) SeedAuthors(authors ...*author.Author) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, a := range authors {
+		c := *a
+		if c.ID == 0 {
+			r.s.lastAuthorID++
+			c.ID = r.s.lastAuthorID
+		} else if c.ID > r.s.lastAuthorID {
+			r.s.lastAuthorID = c.ID
+		}
+		if c.Version == 0 {
+			c.Version = 1
+		}
+		r.s.authors[c.ID] = &c
+		r.s.authorOwners[c.ID] = ""
+	}
+}
+
+// GetAuthor retrieves a single author by ID.
+func (r *AuthorRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	a, ok := r.s.authors[id]
+	if !ok || a.DeletedAt.Valid || !visible(ctx, r.s.authorOwners[id]) {
+		return nil, apperrors.NotFoundError
+	}
+	c := *a
+	return &c, nil
+}
+
+// GetAuthorsByIDs retrieves the authors with the given IDs in the order of
+// ids and reports the IDs that were not found.
+func (r *AuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var found []*author.Author
+	for _, id := range ids {
+		a, ok := r.s.authors[id]
+		if !ok || a.DeletedAt.Valid || !visible(ctx, r.s.authorOwners[id]) {
+			continue
+		}
+		c := *a
+		found = append(found, &c)
+	}
+	authors, missing := author.MatchIDs(ids, found)
+	return authors, missing, nil
+}
+
+// ListAuthors retrieves all authors ordered by name.
+func (r *AuthorRepository) ListAuthors(ctx context.Context) ([]*author.Author, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.sorted(ctx, false), nil
+}
+
+// ListAuthorsPage retrieves up to params.Limit authors ordered by (name, id),
+// starting after params.After when set.
+func (r *AuthorRepository) ListAuthorsPage(ctx context.Context, params author.ListAuthorsPageParams) ([]*author.Author, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	result := []*author.Author{}
+	for _, a := range r.sorted(ctx, params.IncludeDeleted) {
+		if len(result) == int(params.Limit) {
+			break
+		}
+		if after := params.After; after != nil && (a.Name < after.Name || (a.Name == after.Name && a.ID <= after.ID)) {
+			continue
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+// SearchAuthors finds authors whose bio contains every word of params.Query,
+// filtered by name prefix and bio presence, best matches first.
+func (r *AuthorRepository) SearchAuthors(ctx context.Context, params author.SearchAuthorsParams) ([]*author.Author, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	terms := words(params.Query)
+	type hit struct {
+		a    *author.Author
+		rank int
+	}
+	var hits []hit
+	for _, a := range r.sorted(ctx, false) {
+		if params.NamePrefix != "" && !strings.HasPrefix(a.Name, params.NamePrefix) {
+			continue
+		}
+		if params.HasBio.Valid && (a.Bio.String != "") != params.HasBio.Bool {
+			continue
+		}
+		rank, ok := match(terms, a.Bio.String)
+		if !ok {
+			continue
+		}
+		hits = append(hits, hit{a: a, rank: rank})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].rank > hits[j].rank })
+
+	result := []*author.Author{}
+	for _, h := range hits {
+		if len(result) == int(params.Limit) {
+			break
+		}
+		result = append(result, h.a)
+	}
+	return result, nil
+}
+
+// StreamAuthors calls fn for each live author in ID order. fn runs on a
+// snapshot, so it may call back into the repository.
+func (r *AuthorRepository) StreamAuthors(ctx context.Context, fn func(*author.Author) error) error {
+	r.s.mu.RLock()
+	authors := make([]*author.Author, 0, len(r.s.authors))
+	for _, a := range r.s.authors {
+		if !a.DeletedAt.Valid && visible(ctx, r.s.authorOwners[a.ID]) {
+			c := *a
+			authors = append(authors, &c)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sort.Slice(authors, func(i, j int) bool { return authors[i].ID < authors[j].ID })
+	for _, a := range authors {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAuthor creates a new author.
+func (r *AuthorRepository) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	c := *r.insert(ctx, params)
+	return &c, nil
+}
+
+// UpsertAuthor creates or updates the author with params.ExternalID in the
+// tenant of ctx, restoring it if it was soft-deleted.
+func (r *AuthorRepository) UpsertAuthor(ctx context.Context, params author.UpsertAuthorParams) (*author.Author, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	owner := tenant.FromContext(ctx)
+	for id, a := range r.s.authors {
+		if r.s.authorOwners[id] == owner && a.ExternalID.Valid && a.ExternalID.String == params.ExternalID {
+			a.Name = params.Name
+			a.Bio = params.Bio
+			a.DeletedAt = pgtype.Timestamptz{}
+			a.Version++
+			c := *a
+			return &c, false, nil
+		}
+	}
+	a := r.insert(ctx, author.CreateAuthorParams{Name: params.Name, Bio: params.Bio})
+	a.ExternalID = pgtype.Text{String: params.ExternalID, Valid: true}
+	c := *a
+	return &c, true, nil
+}
+
+// BulkCreateAuthors inserts all params and returns the new authors.
+func (r *AuthorRepository) BulkCreateAuthors(ctx context.Context, params []author.CreateAuthorParams) ([]*author.Author, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	created := make([]*author.Author, 0, len(params))
+	for _, p := range params {
+		c := *r.insert(ctx, p)
+		created = append(created, &c)
+	}
+	return created, nil
+}
+
+// UpdateAuthor updates an existing author if its version still matches.
+func (r *AuthorRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, err := r.live(ctx, params.ID, params.Version)
+	if err != nil {
+		return err
+	}
+	a.Name = params.Name
+	a.Bio = params.Bio
+	a.Version++
+	return nil
+}
+
+// PatchAuthor updates the fields set in params if the version still matches.
+func (r *AuthorRepository) PatchAuthor(ctx context.Context, params author.PatchAuthorParams) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, err := r.live(ctx, params.ID, params.Version)
+	if err != nil {
+		return err
+	}
+	if params.Name.Set && !params.Name.Null {
+		a.Name = params.Name.Value
+	}
+	if params.Bio.Set {
+		a.Bio = pgtype.Text{String: params.Bio.Value, Valid: !params.Bio.Null}
+	}
+	a.Version++
+	return nil
+}
+
+// DeleteAuthor soft-deletes an author if its version still matches.
+func (r *AuthorRepository) DeleteAuthor(ctx context.Context, params author.DeleteAuthorParams) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, err := r.live(ctx, params.ID, params.Version)
+	if err != nil {
+		return err
+	}
+	a.DeletedAt = pgtype.Timestamptz{Time: r.s.now(), Valid: true}
+	a.Version++
+	return nil
+}
+
+// RestoreAuthor clears the soft-delete marker of an author.
+func (r *AuthorRepository) RestoreAuthor(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, ok := r.s.authors[id]
+	if !ok || !a.DeletedAt.Valid || !visible(ctx, r.s.authorOwners[id]) {
+		return apperrors.NotFoundError
+	}
+	a.DeletedAt = pgtype.Timestamptz{}
+	a.Version++
+	return nil
+}
+
+// PurgeDeletedAuthors permanently removes authors soft-deleted before
+// deletedBefore, together with their books.
+func (r *AuthorRepository) PurgeDeletedAuthors(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var n int64
+	for id, a := range r.s.authors {
+		if a.DeletedAt.Valid && a.DeletedAt.Time.Before(deletedBefore) && visible(ctx, r.s.authorOwners[id]) {
+			delete(r.s.authors, id)
+			delete(r.s.authorOwners, id)
+			n++
+		}
+	}
+	for id, b := range r.s.books {
+		if _, ok := r.s.authors[b.AuthorID]; !ok {
+			delete(r.s.books, id)
+			delete(r.s.bookOwners, id)
+		}
+	}
+	return n, nil
+}
+
+// insert stores a new author owned by the tenant of ctx. The caller must
+// hold the write lock.
+func (r *AuthorRepository) insert(ctx context.Context, params author.CreateAuthorParams) *author.Author {
+	r.s.lastAuthorID++
+	a := &author.Author{
+		ID:      r.s.lastAuthorID,
+		Name:    params.Name,
+		Bio:     params.Bio,
+		Version: 1,
+	}
+	r.s.authors[a.ID] = a
+	r.s.authorOwners[a.ID] = tenant.FromContext(ctx)
+	return a
+}
+
+// live returns the live author with the given ID visible to ctx, checking
+// version unless it is 0. The caller must hold the write lock.
+func (r *AuthorRepository) live(ctx context.Context, id int64, version int32) (*author.Author, error) {
+	a, ok := r.s.authors[id]
+	if !ok || a.DeletedAt.Valid || !visible(ctx, r.s.authorOwners[id]) {
+		return nil, apperrors.NotFoundError
+	}
+	if version != 0 && a.Version != version {
+		return nil, apperrors.PreconditionFailedError
+	}
+	return a, nil
+}
+
+// sorted returns copies of the authors visible to ctx ordered by (name, id).
+// The caller must hold the read lock.
+func (r *AuthorRepository) sorted(ctx context.Context, includeDeleted bool) []*author.Author {
+	result := make([]*author.Author, 0, len(r.s.authors))
+	for _, a := range r.s.authors {
+		if (a.DeletedAt.Valid && !includeDeleted) || !visible(ctx, r.s.authorOwners[a.ID]) {
+			continue
+		}
+		c := *a
+		result = append(result, &c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// words splits s into lower-cased words.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// match reports whether text contains every term and ranks it by the number
+// of term occurrences. No terms matches everything with rank 0.
+func match(terms []string, text string) (int, bool) {
+	if len(terms) == 0 {
+		return 0, true
+	}
+	counts := make(map[string]int)
+	for _, w := range words(text) {
+		counts[w]++
+	}
+	rank := 0
+	for _, t := range terms {
+		if counts[t] == 0 {
+			return 0, false
+		}
+		rank += counts[t]
+	}
+	return rank, true
+}
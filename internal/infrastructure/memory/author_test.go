@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author/authortest"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestAuthorRepository_CreateAssignsSequentialIDs(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+	ctx := context.Background()
+
+	// Act
+	first, _ := repo.CreateAuthor(ctx, author.CreateAuthorParams{Name: "Bob"})
+	second, _ := repo.CreateAuthor(ctx, author.CreateAuthorParams{Name: "Alice"})
+
+	// Assert
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("expected IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+	if first.Version != 1 {
+		t.Errorf("expected version 1, got %d", first.Version)
+	}
+	all, _ := repo.ListAuthors(ctx)
+	if len(all) != 2 || all[0].Name != "Alice" || all[1].Name != "Bob" {
+		t.Errorf("expected authors ordered by name, got %+v", all)
+	}
+}
+
+func TestAuthorRepository_ReturnsCopies(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+	ctx := context.Background()
+	created, _ := repo.CreateAuthor(ctx, author.CreateAuthorParams{Name: "Alice"})
+
+	// Act
+	created.Name = "Mallory"
+	got, _ := repo.GetAuthor(ctx, created.ID)
+
+	// Assert
+	if got.Name != "Alice" {
+		t.Errorf("expected stored author to be unaffected, got '%s'", got.Name)
+	}
+}
+
+func TestAuthorRepository_GetNotFound(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+
+	// Act
+	a, err := repo.GetAuthor(context.Background(), 42)
+
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) || a != nil {
+		t.Errorf("expected not found error, got %v, %v", a, err)
+	}
+}
+
+func TestAuthorRepository_UpdateChecksVersion(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+	repo.SeedAuthors(&author.Author{ID: 1, Name: "Alice", Version: 3})
+	ctx := context.Background()
+
+	// Act
+	staleErr := repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: 1, Name: "Stale", Version: 2})
+	okErr := repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: 1, Name: "Fresh", Version: 3})
+	missingErr := repo.UpdateAuthor(ctx, author.UpdateAuthorParams{ID: 2, Name: "Ghost"})
+
+	// Assert
+	if !errors.Is(staleErr, apperrors.PreconditionFailedError) {
+		t.Errorf("expected precondition failed error, got %v", staleErr)
+	}
+	if okErr != nil {
+		t.Errorf("unexpected error: %v", okErr)
+	}
+	if !errors.Is(missingErr, apperrors.NotFoundError) {
+		t.Errorf("expected not found error, got %v", missingErr)
+	}
+	got, _ := repo.GetAuthor(ctx, 1)
+	if got.Name != "Fresh" || got.Version != 4 {
+		t.Errorf("expected Fresh at version 4, got %s at %d", got.Name, got.Version)
+	}
+}
+
+func TestAuthorRepository_SoftDeleteAndRestore(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+	repo.SeedAuthors(&author.Author{ID: 1, Name: "Alice"})
+	ctx := context.Background()
+
+	// Act
+	deleteErr := repo.DeleteAuthor(ctx, author.DeleteAuthorParams{ID: 1, Version: 1})
+	_, getErr := repo.GetAuthor(ctx, 1)
+	withDeleted, _ := repo.ListAuthorsPage(ctx, author.ListAuthorsPageParams{Limit: 10, IncludeDeleted: true})
+	restoreErr := repo.RestoreAuthor(ctx, 1)
+	restoreAgainErr := repo.RestoreAuthor(ctx, 1)
+
+	// Assert
+	if deleteErr != nil {
+		t.Fatalf("unexpected delete error: %v", deleteErr)
+	}
+	if !errors.Is(getErr, apperrors.NotFoundError) {
+		t.Errorf("expected deleted author to be hidden, got %v", getErr)
+	}
+	if len(withDeleted) != 1 || !withDeleted[0].DeletedAt.Valid {
+		t.Errorf("expected deleted author with include_deleted, got %+v", withDeleted)
+	}
+	if restoreErr != nil {
+		t.Errorf("unexpected restore error: %v", restoreErr)
+	}
+	if !errors.Is(restoreAgainErr, apperrors.NotFoundError) {
+		t.Errorf("expected not found restoring a live author, got %v", restoreAgainErr)
+	}
+	if got, _ := repo.GetAuthor(ctx, 1); got.Version != 3 {
+		t.Errorf("expected version 3 after delete and restore, got %d", got.Version)
+	}
+}
+
+func TestAuthorRepository_ListAuthorsPage(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+	repo.SeedAuthors(
+		&author.Author{ID: 3, Name: "Carol"},
+		&author.Author{ID: 1, Name: "Alice"},
+		&author.Author{ID: 4, Name: "Bob"},
+		&author.Author{ID: 2, Name: "Bob"},
+	)
+
+	// Act
+	page, _ := repo.ListAuthorsPage(context.Background(), author.ListAuthorsPageParams{
+		After: &author.Cursor{Name: "Bob", ID: 2},
+		Limit: 5,
+	})
+
+	// Assert
+	if len(page) != 2 || page[0].ID != 4 || page[1].ID != 3 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestAuthorRepository_SearchAuthors(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+	repo.SeedAuthors(
+		&author.Author{ID: 1, Name: "Alice", Bio: pgtype.Text{String: "Writes about gardening.", Valid: true}},
+		&author.Author{ID: 2, Name: "Alfred", Bio: pgtype.Text{String: "Gardening, gardening and more gardening", Valid: true}},
+		&author.Author{ID: 3, Name: "Bob", Bio: pgtype.Text{String: "Gardening expert", Valid: true}},
+		&author.Author{ID: 4, Name: "Alma"},
+	)
+	ctx := context.Background()
+
+	// Act
+	ranked, _ := repo.SearchAuthors(ctx, author.SearchAuthorsParams{Query: "Gardening", NamePrefix: "Al", Limit: 10})
+	noBio, _ := repo.SearchAuthors(ctx, author.SearchAuthorsParams{HasBio: pgtype.Bool{Bool: false, Valid: true}, Limit: 10})
+
+	// Assert
+	if len(ranked) != 2 || ranked[0].ID != 2 || ranked[1].ID != 1 {
+		t.Errorf("expected Alfred then Alice, got %+v", ranked)
+	}
+	if len(noBio) != 1 || noBio[0].ID != 4 {
+		t.Errorf("expected only Alma, got %+v", noBio)
+	}
+}
+
+func TestAuthorRepository_PurgeCascadesToBooks(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := NewStore()
+	authors, books := store.Authors(), store.Books()
+	authors.SeedAuthors(
+		&author.Author{ID: 1, Name: "Old", DeletedAt: pgtype.Timestamptz{Time: now.Add(-48 * time.Hour), Valid: true}},
+		&author.Author{ID: 2, Name: "Live"},
+	)
+	ctx := context.Background()
+	books.CreateBook(ctx, bookParams(1, "Gone"))
+	books.CreateBook(ctx, bookParams(2, "Kept"))
+
+	// Act
+	n, err := authors.PurgeDeletedAuthors(ctx, now.Add(-24*time.Hour))
+
+	// Assert
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 purged author, got %d, %v", n, err)
+	}
+	remaining, _ := books.ListBooks(ctx)
+	if len(remaining) != 1 || remaining[0].Title != "Kept" {
+		t.Errorf("expected only the live author's book, got %+v", remaining)
+	}
+}
+
+func TestAuthorRepository_ConcurrentCreates(t *testing.T) {
+	// Arrange
+	repo := NewAuthorRepository()
+	ctx := context.Background()
+	var wg sync.WaitGroup
+
+	// Act
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo.CreateAuthor(ctx, author.CreateAuthorParams{Name: "Writer"})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	all, _ := repo.ListAuthors(ctx)
+	seen := make(map[int64]bool)
+	for _, a := range all {
+		seen[a.ID] = true
+	}
+	if len(all) != 50 || len(seen) != 50 {
+		t.Errorf("expected 50 authors with unique IDs, got %d authors, %d IDs", len(all), len(seen))
+	}
+}
+
+func TestAuthorRepository_Conformance(t *testing.T) {
+	authortest.RunRepositoryTests(t, func(t *testing.T) author.Repository {
+		return NewAuthorRepository()
+	})
+}
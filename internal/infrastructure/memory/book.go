@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// BookRepository implements the book.Repository interface in memory. Like
+// the PostgreSQL schema it requires the referenced author to exist (even if
+// soft-deleted) in the book's tenant and keeps ISBNs unique per tenant.
+type BookRepository struct {
+	s *Store
+}
+
+// SeedBooks stores copies of books as-is, owned by no tenant and without
+// checking their authors. Books without an ID are assigned the next one.
+func (r *BookRepository) SeedBooks(books ...*book.Book) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, b := range books {
+		c := *b
+		if c.ID == 0 {
+			r.s.lastBookID++
+			c.ID = r.s.lastBookID
+		} else if c.ID > r.s.lastBookID {
+			r.s.lastBookID = c.ID
+		}
+		r.s.books[c.ID] = &c
+		r.s.bookOwners[c.ID] = ""
+	}
+}
+
+// GetBook retrieves a single book by ID.
+func (r *BookRepository) GetBook(ctx context.Context, id int64) (*book.Book, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	b, ok := r.s.books[id]
+	if !ok || !visible(ctx, r.s.bookOwners[id]) {
+		return nil, apperrors.NotFoundError
+	}
+	c := *b
+	return &c, nil
+}
+
+// ListBooks retrieves all books ordered by title.
+func (r *BookRepository) ListBooks(ctx context.Context) ([]*book.Book, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	result := r.filter(ctx, func(*book.Book) bool { return true })
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Title != result[j].Title {
+			return result[i].Title < result[j].Title
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// ListBooksByAuthor retrieves an author's books ordered by publication date,
+// undated books last.
+func (r *BookRepository) ListBooksByAuthor(ctx context.Context, authorID int64) ([]*book.Book, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	result := r.filter(ctx, func(b *book.Book) bool { return b.AuthorID == authorID })
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.PublishedAt.Valid != b.PublishedAt.Valid {
+			return a.PublishedAt.Valid
+		}
+		if !a.PublishedAt.Time.Equal(b.PublishedAt.Time) {
+			return a.PublishedAt.Time.Before(b.PublishedAt.Time)
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.ID < b.ID
+	})
+	return result, nil
+}
+
+// CreateBook creates a new book.
+func (r *BookRepository) CreateBook(ctx context.Context, params book.CreateBookParams) (*book.Book, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	b := &book.Book{
+		AuthorID:    params.AuthorID,
+		Title:       params.Title,
+		ISBN:        params.ISBN,
+		PublishedAt: params.PublishedAt,
+	}
+	owner := tenant.FromContext(ctx)
+	if err := r.check(owner, b); err != nil {
+		return nil, err
+	}
+	r.s.lastBookID++
+	b.ID = r.s.lastBookID
+	r.s.books[b.ID] = b
+	r.s.bookOwners[b.ID] = owner
+
+	c := *b
+	return &c, nil
+}
+
+// UpdateBook updates an existing book.
+func (r *BookRepository) UpdateBook(ctx context.Context, params book.UpdateBookParams) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.books[params.ID]; !ok || !visible(ctx, r.s.bookOwners[params.ID]) {
+		return apperrors.NotFoundError
+	}
+	b := &book.Book{
+		ID:          params.ID,
+		AuthorID:    params.AuthorID,
+		Title:       params.Title,
+		ISBN:        params.ISBN,
+		PublishedAt: params.PublishedAt,
+	}
+	if err := r.check(r.s.bookOwners[b.ID], b); err != nil {
+		return err
+	}
+	r.s.books[b.ID] = b
+	return nil
+}
+
+// DeleteBook permanently deletes a book.
+func (r *BookRepository) DeleteBook(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.books[id]; !ok || !visible(ctx, r.s.bookOwners[id]) {
+		return apperrors.NotFoundError
+	}
+	delete(r.s.books, id)
+	delete(r.s.bookOwners, id)
+	return nil
+}
+
+// check enforces the constraints the PostgreSQL schema declares for b owned
+// by owner. The caller must hold the write lock.
+func (r *BookRepository) check(owner string, b *book.Book) error {
+	if _, ok := r.s.authors[b.AuthorID]; !ok || r.s.authorOwners[b.AuthorID] != owner {
+		return apperrors.ConflictError("referenced resource does not exist or is still referenced", nil)
+	}
+	if b.ISBN.Valid {
+		for _, other := range r.s.books {
+			if other.ID != b.ID && r.s.bookOwners[other.ID] == owner && other.ISBN.Valid && other.ISBN.String == b.ISBN.String {
+				return apperrors.ConflictError("resource already exists", nil)
+			}
+		}
+	}
+	return nil
+}
+
+// filter returns copies of the books visible to ctx that satisfy keep. The
+// caller must hold the read lock.
+func (r *BookRepository) filter(ctx context.Context, keep func(*book.Book) bool) []*book.Book {
+	result := []*book.Book{}
+	for _, b := range r.s.books {
+		if visible(ctx, r.s.bookOwners[b.ID]) && keep(b) {
+			c := *b
+			result = append(result, &c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func bookParams(authorID int64, title string) book.CreateBookParams {
+	return book.CreateBookParams{AuthorID: authorID, Title: title}
+}
+
+func TestBookRepository_CreateRequiresAuthor(t *testing.T) {
+	// Arrange
+	repo := NewStore().Books()
+
+	// Act
+	_, err := repo.CreateBook(context.Background(), bookParams(1, "Orphan"))
+
+	// Assert
+	if !errors.Is(err, apperrors.ConflictError("", nil)) {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
+func TestBookRepository_UniqueISBN(t *testing.T) {
+	// Arrange
+	store := NewStore()
+	store.Authors().SeedAuthors(&author.Author{ID: 1, Name: "Alice"})
+	repo := store.Books()
+	ctx := context.Background()
+	params := bookParams(1, "First")
+	params.ISBN = pgtype.Text{String: "978-0000000001", Valid: true}
+	first, _ := repo.CreateBook(ctx, params)
+
+	// Act
+	params.Title = "Second"
+	_, createErr := repo.CreateBook(ctx, params)
+	updateErr := repo.UpdateBook(ctx, book.UpdateBookParams{ID: first.ID, AuthorID: 1, Title: "Renamed", ISBN: params.ISBN})
+
+	// Assert
+	if !errors.Is(createErr, apperrors.ConflictError("", nil)) {
+		t.Errorf("expected conflict error, got %v", createErr)
+	}
+	if updateErr != nil {
+		t.Errorf("expected a book to keep its own ISBN, got %v", updateErr)
+	}
+}
+
+func TestBookRepository_ListBooksByAuthorOrder(t *testing.T) {
+	// Arrange
+	store := NewStore()
+	store.Authors().SeedAuthors(&author.Author{ID: 1, Name: "Alice"}, &author.Author{ID: 2, Name: "Bob"})
+	repo := store.Books()
+	ctx := context.Background()
+	date := func(year int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	repo.CreateBook(ctx, book.CreateBookParams{AuthorID: 1, Title: "Undated"})
+	repo.CreateBook(ctx, book.CreateBookParams{AuthorID: 1, Title: "Later", PublishedAt: date(2020)})
+	repo.CreateBook(ctx, book.CreateBookParams{AuthorID: 1, Title: "Earlier", PublishedAt: date(2010)})
+	repo.CreateBook(ctx, book.CreateBookParams{AuthorID: 2, Title: "Other"})
+
+	// Act
+	books, err := repo.ListBooksByAuthor(ctx, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var titles []string
+	for _, b := range books {
+		titles = append(titles, b.Title)
+	}
+	if len(titles) != 3 || titles[0] != "Earlier" || titles[1] != "Later" || titles[2] != "Undated" {
+		t.Errorf("unexpected order: %v", titles)
+	}
+}
+
+func TestBookRepository_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewStore().Books()
+	ctx := context.Background()
+
+	// Act
+	_, getErr := repo.GetBook(ctx, 1)
+	updateErr := repo.UpdateBook(ctx, book.UpdateBookParams{ID: 1, AuthorID: 1, Title: "Ghost"})
+	deleteErr := repo.DeleteBook(ctx, 1)
+
+	// Assert
+	for _, err := range []error{getErr, updateErr, deleteErr} {
+		if !errors.Is(err, apperrors.NotFoundError) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	}
+}
+
+func TestBookRepository_SeedBooks(t *testing.T) {
+	// Arrange
+	store := NewStore()
+	store.Authors().SeedAuthors(&author.Author{ID: 1, Name: "Alice"})
+	repo := store.Books()
+	ctx := context.Background()
+
+	// Act
+	repo.SeedBooks(&book.Book{ID: 5, AuthorID: 1, Title: "Seeded"})
+	created, err := repo.CreateBook(ctx, bookParams(1, "Created"))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID != 6 {
+		t.Errorf("expected the next ID after the seeded book, got %d", created.ID)
+	}
+	if b, err := repo.GetBook(ctx, 5); err != nil || b.Title != "Seeded" {
+		t.Errorf("expected the seeded book, got %+v, %v", b, err)
+	}
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/idempotency"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+)
+
+// IdempotencyStore implements idempotency.Store in memory. Expired keys are
+// dropped whenever a key is reserved, so no purge job is needed.
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]*idempotency.Record
+}
+
+// idempotencyKey identifies a key within its tenant.
+type idempotencyKey struct {
+	tenant, key string
+}
+
+// NewIdempotencyStore creates an empty IdempotencyStore.
+func NewIdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{records: make(map[idempotencyKey]*idempotency.Record)}
+}
+
+// Reserve claims key unless an unexpired request holds it.
+func (s *IdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, now, expiresAt time.Time) (*idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteExpired(func(string) bool { return true }, now)
+	k := idempotencyKey{tenant.FromContext(ctx), key}
+	if rec, ok := s.records[k]; ok {
+		return copyRecord(rec), false, nil
+	}
+	rec := &idempotency.Record{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}
+	s.records[k] = rec
+	return copyRecord(rec), true, nil
+}
+
+// Complete stores the response to the request holding key.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, resp idempotency.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.records[idempotencyKey{tenant.FromContext(ctx), key}]; ok {
+		rec.Response = &resp
+	}
+	return nil
+}
+
+// Release deletes key if its request has not completed.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := idempotencyKey{tenant.FromContext(ctx), key}
+	if rec, ok := s.records[k]; ok && rec.Response == nil {
+		delete(s.records, k)
+	}
+	return nil
+}
+
+// DeleteExpired deletes the expired keys visible to the tenant of ctx.
+func (s *IdempotencyStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteExpired(func(owner string) bool { return visible(ctx, owner) }, now), nil
+}
+
+// deleteExpired deletes the keys expired at now whose tenant matches. The
+// caller must hold the lock.
+func (s *IdempotencyStore) deleteExpired(match func(tenant string) bool, now time.Time) int64 {
+	var n int64
+	for k, rec := range s.records {
+		if match(k.tenant) && !rec.ExpiresAt.After(now) {
+			delete(s.records, k)
+			n++
+		}
+	}
+	return n
+}
+
+// copyRecord copies rec so callers cannot change the stored record.
+func copyRecord(rec *idempotency.Record) *idempotency.Record {
+	c := *rec
+	if rec.Response != nil {
+		resp := *rec.Response
+		c.Response = &resp
+	}
+	return &c
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/idempotency"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+)
+
+func TestIdempotencyStore_CompleteAndRelease(t *testing.T) {
+	// Arrange
+	store := NewIdempotencyStore()
+	ctx := context.Background()
+	now := time.Now()
+	store.Reserve(ctx, "done", "fp", now, now.Add(time.Hour))
+	store.Reserve(ctx, "failed", "fp", now, now.Add(time.Hour))
+
+	// Act
+	store.Complete(ctx, "done", idempotency.Response{Status: 201, Body: []byte("{}")})
+	store.Release(ctx, "done")
+	store.Release(ctx, "failed")
+
+	// Assert
+	rec, ok, _ := store.Reserve(ctx, "done", "other", now, now.Add(time.Hour))
+	if ok || rec.Fingerprint != "fp" || rec.Response == nil || rec.Response.Status != 201 {
+		t.Errorf("expected the completed key to be kept, got %+v (reserved %t)", rec, ok)
+	}
+	if _, ok, _ := store.Reserve(ctx, "failed", "other", now, now.Add(time.Hour)); !ok {
+		t.Error("expected the released key to be free")
+	}
+}
+
+func TestIdempotencyStore_DeleteExpired(t *testing.T) {
+	// Arrange
+	store := NewIdempotencyStore()
+	now := time.Now()
+	acme := tenant.With(context.Background(), "acme")
+	globex := tenant.With(context.Background(), "globex")
+	store.Reserve(acme, "old", "fp", now, now.Add(time.Minute))
+	store.Reserve(acme, "new", "fp", now, now.Add(time.Hour))
+	store.Reserve(globex, "old", "fp", now, now.Add(time.Minute))
+
+	// Act
+	n, err := store.DeleteExpired(acme, now.Add(time.Minute))
+
+	// Assert
+	if err != nil || n != 1 {
+		t.Errorf("expected to delete acme's expired key, got %d, %v", n, err)
+	}
+	if n, _ := store.DeleteExpired(tenant.With(context.Background(), tenant.All), now.Add(time.Minute)); n != 1 {
+		t.Errorf("expected tenant.All to delete globex's expired key, got %d", n)
+	}
+}
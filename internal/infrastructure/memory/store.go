@@ -0,0 +1,68 @@
+// Package memory provides in-memory implementations of the domain
+// repositories. They mirror the PostgreSQL repositories' semantics (ordering,
+// soft deletes, row versions, tenant isolation and domain errors) so the
+// application can run, and be tested, without a database. State is lost when
+// the process exits.
+//
+// Rows belong to the tenant of the context that created them and are only
+// visible to that tenant or to tenant.All. Unlike PostgreSQL, a context
+// without a tenant is a tenant of its own rather than seeing nothing.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+)
+
+// Store holds the tables shared by the in-memory repositories. Books
+// reference authors, so both live behind one lock to keep foreign-key checks
+// and cascading deletes consistent.
+type Store struct {
+	mu sync.RWMutex
+
+	authors      map[int64]*author.Author
+	lastAuthorID int64
+
+	books      map[int64]*book.Book
+	lastBookID int64
+
+	// authorOwners and bookOwners map the ID of every author and book to
+	// the tenant owning it.
+	authorOwners map[int64]string
+	bookOwners   map[int64]string
+
+	now func() time.Time
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{
+		authors:      make(map[int64]*author.Author),
+		books:        make(map[int64]*book.Book),
+		authorOwners: make(map[int64]string),
+		bookOwners:   make(map[int64]string),
+		now:          time.Now,
+	}
+}
+
+// Authors returns an author.Repository backed by s.
+func (s *Store) Authors() *AuthorRepository {
+	return &AuthorRepository{s: s}
+}
+
+// Books returns a book.Repository backed by s.
+func (s *Store) Books() *BookRepository {
+	return &BookRepository{s: s}
+}
+
+// visible reports whether a row owned by owner is visible to the tenant of
+// ctx.
+func visible(ctx context.Context, owner string) bool {
+	t := tenant.FromContext(ctx)
+	return t == owner || t == tenant.All
+}
@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+)
+
+// TxManager implements transaction.Manager for backends without
+// transactions. fn runs directly and options are ignored: the in-memory
+// repositories apply each change at once, so an error returned by fn does
+// not undo the changes it already made.
+type TxManager struct{}
+
+// NewTxManager creates a new TxManager.
+func NewTxManager() *TxManager {
+	return &TxManager{}
+}
+
+// RunInTx runs fn.
+func (m *TxManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// RunInTxWithOptions runs fn; opts are ignored.
+func (m *TxManager) RunInTxWithOptions(ctx context.Context, opts transaction.Options, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
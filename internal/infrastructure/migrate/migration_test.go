@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/seldomhappy/sqlc-test/migrations"
+)
+
+func TestLoad_OrdersAndPairsFiles(t *testing.T) {
+	// Arrange
+	fsys := fstest.MapFS{
+		"000002_add_index.up.sql":   {Data: []byte("CREATE INDEX i ON t (c);")},
+		"000002_add_index.down.sql": {Data: []byte("DROP INDEX i;")},
+		"000001_create.up.sql":      {Data: []byte("CREATE TABLE t (c int);")},
+		"migrations.go":             {Data: []byte("package migrations")},
+	}
+
+	// Act
+	got, err := Load(fsys)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(got))
+	}
+	if got[0].Version != 1 || got[0].Name != "create" || got[0].Down != "" {
+		t.Errorf("unexpected first migration: %+v", got[0])
+	}
+	if got[1].Version != 2 || got[1].Down != "DROP INDEX i;" {
+		t.Errorf("unexpected second migration: %+v", got[1])
+	}
+	if got[0].Checksum == "" || got[0].Checksum == got[1].Checksum {
+		t.Errorf("expected distinct non-empty checksums")
+	}
+}
+
+func TestLoad_RejectsMissingUp(t *testing.T) {
+	// Arrange
+	fsys := fstest.MapFS{
+		"000001_create.down.sql": {Data: []byte("DROP TABLE t;")},
+	}
+
+	// Act
+	_, err := Load(fsys)
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error for migration without up file")
+	}
+}
+
+func TestLoad_RejectsConflictingNames(t *testing.T) {
+	// Arrange
+	fsys := fstest.MapFS{
+		"000001_create.up.sql": {Data: []byte("CREATE TABLE t (c int);")},
+		"000001_other.up.sql":  {Data: []byte("CREATE TABLE u (c int);")},
+	}
+
+	// Act
+	_, err := Load(fsys)
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error for duplicate version")
+	}
+}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	// Act
+	got, err := Load(migrations.FS)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, mig := range got {
+		if mig.Version != int64(i+1) {
+			t.Errorf("expected contiguous versions, got %d at position %d", mig.Version, i)
+		}
+		if mig.Down == "" {
+			t.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockID is the pg_advisory_lock key serializing concurrent migrators.
+const lockID int64 = 7_239_184_552_013
+
+const createTableSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version    bigint      PRIMARY KEY,
+  name       text        NOT NULL,
+  checksum   text        NOT NULL,
+  applied_at timestamptz NOT NULL DEFAULT now()
+)`
+
+// ErrChecksumMismatch is returned by Up when an applied migration file was
+// edited after it ran. Fix the file or record the new checksum with Force.
+var ErrChecksumMismatch = errors.New("applied migration checksum mismatch")
+
+// Status describes one migration as seen by Status. Unknown is set for
+// versions recorded in schema_migrations that have no migration file.
+type Status struct {
+	Version          int64
+	Name             string
+	Applied          bool
+	AppliedAt        time.Time
+	ChecksumMismatch bool
+	Unknown          bool
+}
+
+// appliedRow is a row of schema_migrations.
+type appliedRow struct {
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies migrations to a PostgreSQL database. All operations hold
+// a session-level advisory lock so concurrent deploys cannot interleave.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// New creates a new Migrator for migrations, as returned by Load.
+func New(pool *pgxpool.Pool, migrations []Migration) *Migrator {
+	return &Migrator{pool: pool, migrations: migrations}
+}
+
+// Up applies every pending migration in version order, each in its own
+// transaction, and returns the migrations applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if row, ok := applied[mig.Version]; ok && row.Checksum != mig.Checksum {
+				return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, mig.Version, mig.Name)
+			}
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := runInTx(ctx, conn, mig.Up,
+				`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+				mig.Version, mig.Name, mig.Checksum); err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the most recently applied steps migrations, newest first,
+// and returns the migrations rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be positive")
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+			}
+			if err := runInTx(ctx, conn, mig.Down,
+				`DELETE FROM schema_migrations WHERE version = $1`, mig.Version); err != nil {
+				return fmt.Errorf("roll back migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status reports every known migration and whether it has been applied,
+// followed by any applied versions with no migration file.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		known := make(map[int64]bool, len(m.migrations))
+		for _, mig := range m.migrations {
+			known[mig.Version] = true
+			s := Status{Version: mig.Version, Name: mig.Name}
+			if row, ok := applied[mig.Version]; ok {
+				s.Applied = true
+				s.AppliedAt = row.AppliedAt
+				s.ChecksumMismatch = row.Checksum != mig.Checksum
+			}
+			statuses = append(statuses, s)
+		}
+		for version, row := range applied {
+			if !known[version] {
+				statuses = append(statuses, Status{
+					Version:   version,
+					Name:      row.Name,
+					Applied:   true,
+					AppliedAt: row.AppliedAt,
+					Unknown:   true,
+				})
+			}
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Force records version as the current schema version without running any
+// SQL: migrations up to version are marked applied with their current
+// checksums and later ones are unmarked. Use it to adopt an existing
+// database or to recover after fixing a failed migration by hand. Version 0
+// unmarks everything.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !m.has(version) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version > $1`, version); err != nil {
+				return err
+			}
+			for _, mig := range m.migrations {
+				if mig.Version > version {
+					break
+				}
+				if _, err := tx.Exec(ctx, `
+INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)
+ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, checksum = EXCLUDED.checksum`,
+					mig.Version, mig.Name, mig.Checksum); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// has reports whether a migration with version exists.
+func (m *Migrator) has(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory
+// lock, creating schema_migrations first if needed.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	c, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer c.Release()
+	conn := c.Conn()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	if _, err := conn.Exec(ctx, createTableSQL); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// loadApplied reads schema_migrations keyed by version.
+func loadApplied(ctx context.Context, conn *pgx.Conn) (map[int64]appliedRow, error) {
+	rows, err := conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedRow)
+	for rows.Next() {
+		var version int64
+		var row appliedRow
+		if err := rows.Scan(&version, &row.Name, &row.Checksum, &row.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = row
+	}
+	return applied, rows.Err()
+}
+
+// runInTx executes a migration body and its bookkeeping statement atomically.
+func runInTx(ctx context.Context, conn *pgx.Conn, body, bookkeeping string, args ...any) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, body); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, bookkeeping, args...)
+		return err
+	})
+}
@@ -0,0 +1,95 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore implements Store in memory, for the database drivers without
+// an outbox table. Messages are lost when the process exits, so delivery is
+// only at-least-once while it keeps running.
+type MemoryStore struct {
+	mu     sync.Mutex
+	msgs   []*memoryMessage
+	lastID int64
+}
+
+type memoryMessage struct {
+	Message
+	nextAttempt time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append adds a message to the outbox.
+func (s *MemoryStore) Append(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	msg.ID = s.lastID
+	s.msgs = append(s.msgs, &memoryMessage{Message: msg, nextAttempt: msg.CreatedAt})
+	return nil
+}
+
+// Claim leases up to limit due messages until until, oldest first.
+func (s *MemoryStore) Claim(ctx context.Context, limit int32, now, until time.Time) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Message
+	for _, m := range s.msgs {
+		if len(due) == int(limit) {
+			break
+		}
+		if !m.nextAttempt.After(now) {
+			m.nextAttempt = until
+			due = append(due, m.Message)
+		}
+	}
+	return due, nil
+}
+
+// MarkPublished records that a message was delivered and drops it.
+func (s *MemoryStore) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.msgs {
+		if m.ID == id {
+			s.msgs = append(s.msgs[:i], s.msgs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt.
+func (s *MemoryStore) MarkFailed(ctx context.Context, id int64, next time.Time, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.msgs {
+		if m.ID == id {
+			m.Attempts++
+			m.nextAttempt = next
+		}
+	}
+	return nil
+}
+
+// Pending returns the messages not yet published, oldest first.
+func (s *MemoryStore) Pending() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs := make([]Message, len(s.msgs))
+	for i, m := range s.msgs {
+		msgs[i] = m.Message
+	}
+	return msgs
+}
@@ -0,0 +1,85 @@
+// Package outbox implements the transactional outbox: domain events are
+// stored in the same transaction as the change they describe, and a Relay
+// later publishes them to one or more Sinks. Delivery is at-least-once, so
+// sinks and their consumers must tolerate duplicates; messages are not
+// guaranteed to arrive in order.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+)
+
+// Message is an event waiting in, or published from, the outbox.
+type Message struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int64           `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Attempts      int32           `json:"-"`
+}
+
+// Store persists outbox messages. Implementations join the transaction
+// carried by ctx, if any.
+type Store interface {
+	// Append adds a message; its ID is assigned by the store.
+	Append(ctx context.Context, msg Message) error
+	// Claim leases up to limit unpublished messages due at now, oldest
+	// first: no other Claim returns them before until, unless MarkFailed
+	// schedules them earlier. Messages whose lease expires unmarked are
+	// claimed again.
+	Claim(ctx context.Context, limit int32, now, until time.Time) ([]Message, error)
+	// MarkPublished records that a message was delivered.
+	MarkPublished(ctx context.Context, id int64, at time.Time) error
+	// MarkFailed records a failed delivery and when to try again.
+	MarkFailed(ctx context.Context, id int64, next time.Time, cause error) error
+}
+
+// AuthorEvents implements author.EventRecorder by appending events to a
+// Store.
+type AuthorEvents struct {
+	store Store
+}
+
+// NewAuthorEvents creates an AuthorEvents writing to store.
+func NewAuthorEvents(store Store) *AuthorEvents {
+	return &AuthorEvents{store: store}
+}
+
+// authorPayload is the JSON form of an author.Event, tagged with the tenant
+// it happened in.
+type authorPayload struct {
+	Type       author.EventType `json:"type"`
+	AuthorID   int64            `json:"author_id"`
+	Author     *author.Author   `json:"author,omitempty"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	TenantID   string           `json:"tenant_id,omitempty"`
+}
+
+// RecordEvent appends e, tagged with the tenant of ctx, to the outbox.
+func (r *AuthorEvents) RecordEvent(ctx context.Context, e author.Event) error {
+	payload, err := json.Marshal(authorPayload{
+		Type:       e.Type,
+		AuthorID:   e.AuthorID,
+		Author:     e.Author,
+		OccurredAt: e.OccurredAt,
+		TenantID:   tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", e.Type, err)
+	}
+	return r.store.Append(ctx, Message{
+		AggregateType: "author",
+		AggregateID:   e.AuthorID,
+		EventType:     string(e.Type),
+		Payload:       payload,
+		CreatedAt:     e.OccurredAt,
+	})
+}
@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/tutorial"
+)
+
+// PostgresStore implements Store on the outbox table.
+type PostgresStore struct {
+	queries *tutorial.Queries
+}
+
+// NewPostgresStore creates a new PostgresStore.
+func NewPostgresStore(db tutorial.DBTX) *PostgresStore {
+	return &PostgresStore{queries: tutorial.New(db)}
+}
+
+// q returns the queries bound to the transaction carried by ctx, if any.
+func (s *PostgresStore) q(ctx context.Context) *tutorial.Queries {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return s.queries.WithTx(tx)
+	}
+	return s.queries
+}
+
+// Append adds a message to the outbox.
+func (s *PostgresStore) Append(ctx context.Context, msg Message) error {
+	return s.q(ctx).AppendOutbox(ctx, tutorial.AppendOutboxParams{
+		AggregateType: msg.AggregateType,
+		AggregateID:   msg.AggregateID,
+		EventType:     msg.EventType,
+		Payload:       msg.Payload,
+		CreatedAt:     pgtype.Timestamptz{Time: msg.CreatedAt, Valid: true},
+	})
+}
+
+// Claim leases up to limit due messages until until in a single statement,
+// skipping those another relay is claiming at the same time. It needs no
+// surrounding transaction.
+func (s *PostgresStore) Claim(ctx context.Context, limit int32, now, until time.Time) ([]Message, error) {
+	rows, err := s.q(ctx).ClaimOutbox(ctx, tutorial.ClaimOutboxParams{
+		LeaseUntil: pgtype.Timestamptz{Time: until, Valid: true},
+		Now:        pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]Message, len(rows))
+	for i, row := range rows {
+		msgs[i] = Message{
+			ID:            row.ID,
+			AggregateType: row.AggregateType,
+			AggregateID:   row.AggregateID,
+			EventType:     row.EventType,
+			Payload:       row.Payload,
+			CreatedAt:     row.CreatedAt.Time,
+			Attempts:      row.Attempts,
+		}
+	}
+	return msgs, nil
+}
+
+// MarkPublished records that a message was delivered.
+func (s *PostgresStore) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	return s.q(ctx).MarkOutboxPublished(ctx, tutorial.MarkOutboxPublishedParams{
+		ID:          id,
+		PublishedAt: pgtype.Timestamptz{Time: at, Valid: true},
+	})
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt.
+func (s *PostgresStore) MarkFailed(ctx context.Context, id int64, next time.Time, cause error) error {
+	return s.q(ctx).MarkOutboxFailed(ctx, tutorial.MarkOutboxFailedParams{
+		ID:            id,
+		NextAttemptAt: pgtype.Timestamptz{Time: next, Valid: true},
+		LastError:     pgtype.Text{String: cause.Error(), Valid: true},
+	})
+}
+
+// PurgePublished deletes messages published before before and returns how
+// many were deleted.
+func (s *PostgresStore) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	return s.q(ctx).PurgePublishedOutbox(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+}
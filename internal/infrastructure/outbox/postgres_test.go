@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/migrate"
+	"github.com/seldomhappy/sqlc-test/migrations"
+)
+
+// TestPostgresStore is skipped unless TEST_DATABASE_URL points at a
+// disposable database: the outbox table is truncated.
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrate.New(pool, all).Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "TRUNCATE outbox RESTART IDENTITY"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	store := NewPostgresStore(pool)
+	tx := database.NewTxManager(pool, transaction.Options{}, 0)
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := store.Append(ctx, Message{AggregateType: "author", AggregateID: 1, EventType: "author.created", Payload: json.RawMessage(`{}`), CreatedAt: now}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	// A leased message is skipped by later claims until the lease expires.
+	lease := now.Add(time.Minute)
+	msgs, err := store.Claim(ctx, 1, now.Add(time.Second), lease)
+	if err != nil || len(msgs) != 1 || msgs[0].ID != 1 {
+		t.Fatalf("claim: %v, %+v", err, msgs)
+	}
+	msgs, err = store.Claim(ctx, 10, now.Add(time.Second), lease)
+	if err != nil || len(msgs) != 1 || msgs[0].ID != 2 {
+		t.Errorf("second claim: %v, %+v", err, msgs)
+	}
+	msgs, err = store.Claim(ctx, 10, lease, lease.Add(time.Minute))
+	if err != nil || len(msgs) != 2 {
+		t.Errorf("expected both messages after the lease expired, got %v, %+v", err, msgs)
+	}
+
+	err = tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := store.MarkPublished(ctx, 1, now); err != nil {
+			return err
+		}
+		return store.MarkFailed(ctx, 2, now.Add(time.Hour), errors.New("boom"))
+	})
+	if err != nil {
+		t.Fatalf("mark: %v", err)
+	}
+	msgs, err = store.Claim(ctx, 10, now.Add(30*time.Minute), now.Add(time.Hour))
+	if err != nil || len(msgs) != 0 {
+		t.Errorf("expected nothing due, got %v, %+v", err, msgs)
+	}
+
+	// Only messages published before the cutoff are purged.
+	if n, err := store.PurgePublished(ctx, now); err != nil || n != 0 {
+		t.Errorf("purge at publish time: %v, %d", err, n)
+	}
+	if n, err := store.PurgePublished(ctx, now.Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("purge after publish time: %v, %d", err, n)
+	}
+}
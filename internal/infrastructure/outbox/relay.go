@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+)
+
+// Retry backoff: the delay before attempt n+1 is retryBackoff << n, capped
+// at maxRetryBackoff.
+const (
+	retryBackoff    = time.Second
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// claimLease is how long a claimed batch is hidden from other relays while
+// it is delivered. A batch still undelivered when it expires, e.g. because
+// the relay died, is claimed and delivered again.
+const claimLease = 5 * time.Minute
+
+// Relay publishes pending outbox messages to its sinks. A message is marked
+// published only once every sink accepted it; otherwise it is retried with
+// exponential backoff, and sinks that already accepted it will see it again.
+//
+// No transaction is held while sinks are called: a batch is leased by a
+// single Claim, delivered, and its outcome recorded in a short transaction.
+type Relay struct {
+	store     Store
+	tx        transaction.Manager
+	sinks     []Sink
+	interval  time.Duration
+	batchSize int32
+	lease     time.Duration
+	now       func() time.Time
+}
+
+// NewRelay creates a Relay that polls store every interval and publishes up
+// to batchSize messages per batch.
+func NewRelay(store Store, tx transaction.Manager, sinks []Sink, interval time.Duration, batchSize int32) *Relay {
+	return &Relay{
+		store:     store,
+		tx:        tx,
+		sinks:     sinks,
+		interval:  interval,
+		batchSize: batchSize,
+		lease:     claimLease,
+		now:       time.Now,
+	}
+}
+
+// Run publishes messages until ctx is done. Full batches are followed
+// immediately by the next one; otherwise Run waits for the next tick.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		n, err := r.PublishBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		if err == nil && n == int(r.batchSize) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishBatch publishes one batch of due messages and returns how many it
+// handled, delivered or not.
+func (r *Relay) PublishBatch(ctx context.Context) (int, error) {
+	now := r.now()
+	msgs, err := r.store.Claim(ctx, r.batchSize, now, now.Add(r.lease))
+	if err != nil {
+		return 0, fmt.Errorf("claim messages: %w", err)
+	}
+
+	results := make([]error, len(msgs))
+	for i, msg := range msgs {
+		results[i] = r.publish(ctx, msg)
+	}
+
+	err = r.tx.RunInTx(ctx, func(ctx context.Context) error {
+		for i, msg := range msgs {
+			if results[i] != nil {
+				next := r.now().Add(backoff(msg.Attempts))
+				if err := r.store.MarkFailed(ctx, msg.ID, next, results[i]); err != nil {
+					return fmt.Errorf("mark message %d failed: %w", msg.ID, err)
+				}
+				continue
+			}
+			if err := r.store.MarkPublished(ctx, msg.ID, r.now()); err != nil {
+				return fmt.Errorf("mark message %d published: %w", msg.ID, err)
+			}
+		}
+		return nil
+	})
+	return len(msgs), err
+}
+
+// publish hands msg to every sink and joins their errors.
+func (r *Relay) publish(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, s := range r.sinks {
+		if err := s.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backoff returns the delay before retrying a message that has already
+// failed attempts times.
+func backoff(attempts int32) time.Duration {
+	if attempts >= 20 {
+		return maxRetryBackoff
+	}
+	return min(retryBackoff<<attempts, maxRetryBackoff)
+}
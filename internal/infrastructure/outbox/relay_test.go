@@ -0,0 +1,209 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+)
+
+// fakeSink records what it receives and fails while err is set.
+type fakeSink struct {
+	got []Message
+	err error
+}
+
+func (s *fakeSink) Publish(ctx context.Context, msg Message) error {
+	s.got = append(s.got, msg)
+	return s.err
+}
+
+// sinkFunc adapts a function to Sink.
+type sinkFunc func(ctx context.Context, msg Message) error
+
+func (f sinkFunc) Publish(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// trackingTx reports whether a transaction is open.
+type trackingTx struct {
+	transaction.Manager
+	open bool
+}
+
+func (m *trackingTx) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.Manager.RunInTx(ctx, func(ctx context.Context) error {
+		m.open = true
+		defer func() { m.open = false }()
+		return fn(ctx)
+	})
+}
+
+func newTestRelay(store Store, sinks ...Sink) *Relay {
+	return NewRelay(store, memory.NewTxManager(), sinks, time.Second, 10)
+}
+
+func TestAuthorEvents_RecordEvent(t *testing.T) {
+	// Arrange
+	store := NewMemoryStore()
+	events := NewAuthorEvents(store)
+	a := &author.Author{ID: 7, Name: "Ann", Version: 1}
+
+	// Act
+	err := events.RecordEvent(tenant.With(context.Background(), "acme"), author.NewEvent(author.EventCreated, a.ID, a))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pending := store.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(pending))
+	}
+	msg := pending[0]
+	if msg.AggregateType != "author" || msg.AggregateID != 7 || msg.EventType != "author.created" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	var payload struct {
+		Type     string
+		AuthorID int64 `json:"author_id"`
+		Author   struct{ Name string }
+		TenantID string `json:"tenant_id"`
+	}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Type != "author.created" || payload.AuthorID != 7 || payload.Author.Name != "Ann" || payload.TenantID != "acme" {
+		t.Errorf("unexpected payload %s", msg.Payload)
+	}
+}
+
+func TestRelay_PublishesToAllSinks(t *testing.T) {
+	// Arrange
+	store := NewMemoryStore()
+	store.Append(context.Background(), Message{EventType: "author.created", Payload: json.RawMessage(`{}`)})
+	s1, s2 := &fakeSink{}, &fakeSink{}
+	relay := newTestRelay(store, s1, s2)
+
+	// Act
+	n, err := relay.PublishBatch(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(s1.got) != 1 || len(s2.got) != 1 {
+		t.Errorf("expected one delivery per sink, got n=%d, %d and %d", n, len(s1.got), len(s2.got))
+	}
+	if pending := store.Pending(); len(pending) != 0 {
+		t.Errorf("expected no pending messages, got %d", len(pending))
+	}
+}
+
+func TestRelay_RetriesWithBackoff(t *testing.T) {
+	// Arrange
+	store := NewMemoryStore()
+	now := time.Now()
+	store.Append(context.Background(), Message{EventType: "author.created", CreatedAt: now})
+	sink := &fakeSink{err: errors.New("unavailable")}
+	relay := newTestRelay(store, sink)
+	relay.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Act & Assert
+	relay.PublishBatch(ctx)
+	if pending := store.Pending(); len(pending) != 1 || pending[0].Attempts != 1 {
+		t.Fatalf("expected message to stay pending after a failure, got %+v", pending)
+	}
+
+	if n, _ := relay.PublishBatch(ctx); n != 0 {
+		t.Errorf("expected no retry before the backoff elapsed, got %d", n)
+	}
+
+	now = now.Add(retryBackoff)
+	sink.err = nil
+	relay.PublishBatch(ctx)
+	if pending := store.Pending(); len(pending) != 0 {
+		t.Errorf("expected message to be published on retry, got %d pending", len(pending))
+	}
+	if len(sink.got) != 2 {
+		t.Errorf("expected 2 delivery attempts, got %d", len(sink.got))
+	}
+}
+
+func TestRelay_DeliversOutsideTransaction(t *testing.T) {
+	// Arrange
+	store := NewMemoryStore()
+	store.Append(context.Background(), Message{EventType: "author.created"})
+	txm := &trackingTx{Manager: memory.NewTxManager()}
+	var inTx bool
+	sink := sinkFunc(func(ctx context.Context, msg Message) error {
+		inTx = txm.open
+		return nil
+	})
+	relay := NewRelay(store, txm, []Sink{sink}, time.Second, 10)
+
+	// Act
+	n, err := relay.PublishBatch(context.Background())
+
+	// Assert
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 message handled, got %d, %v", n, err)
+	}
+	if inTx {
+		t.Error("expected sinks to be called with no transaction open")
+	}
+	if pending := store.Pending(); len(pending) != 0 {
+		t.Errorf("expected no pending messages, got %d", len(pending))
+	}
+}
+
+func TestRelay_RedeliversAfterLeaseExpires(t *testing.T) {
+	// Arrange: another relay claimed the message and died before marking it.
+	store := NewMemoryStore()
+	now := time.Now()
+	store.Append(context.Background(), Message{EventType: "author.created", CreatedAt: now})
+	if _, err := store.Claim(context.Background(), 10, now, now.Add(claimLease)); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	sink := &fakeSink{}
+	relay := newTestRelay(store, sink)
+	relay.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Act & Assert
+	if n, _ := relay.PublishBatch(ctx); n != 0 {
+		t.Errorf("expected a leased message to be skipped, got %d", n)
+	}
+
+	now = now.Add(claimLease)
+	if n, _ := relay.PublishBatch(ctx); n != 1 {
+		t.Errorf("expected the message to be claimed once the lease expired, got %d", n)
+	}
+	if len(sink.got) != 1 || len(store.Pending()) != 0 {
+		t.Errorf("expected one delivery and nothing pending, got %d and %d", len(sink.got), len(store.Pending()))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{3, 8 * time.Second},
+		{10, maxRetryBackoff},
+		{100, maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Sink delivers outbox messages to a destination.
+type Sink interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// LogSink writes each message to a logger.
+type LogSink struct {
+	logger *log.Logger
+}
+
+// NewLogSink creates a LogSink writing to logger, or to the standard logger
+// if logger is nil.
+func NewLogSink(logger *log.Logger) *LogSink {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogSink{logger: logger}
+}
+
+// Publish logs msg.
+func (s *LogSink) Publish(ctx context.Context, msg Message) error {
+	s.logger.Printf("outbox: %s %s/%d %s", msg.EventType, msg.AggregateType, msg.AggregateID, msg.Payload)
+	return nil
+}
+
+// WebhookSink POSTs each message as JSON to a URL. Any non-2xx response is
+// a failure. The message ID is sent in the Idempotency-Key header so the
+// receiver can drop redeliveries.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink posting to url with client.
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	return &WebhookSink{url: url, client: client}
+}
+
+// Publish posts msg to the webhook.
+func (s *WebhookSink) Publish(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", strconv.FormatInt(msg.ID, 10))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// FileSink appends each message as a line of JSON to a file.
+type FileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileSink opens path for appending, creating it if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{f: f}, nil
+}
+
+// Publish appends msg to the file and syncs it to disk.
+func (s *FileSink) Publish(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
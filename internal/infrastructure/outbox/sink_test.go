@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWebhookSink_Publish(t *testing.T) {
+	// Arrange
+	var got Message
+	var key string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Idempotency-Key")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	sink := NewWebhookSink(srv.URL, srv.Client())
+
+	// Act
+	err := sink.Publish(context.Background(), Message{ID: 42, EventType: "author.created", Payload: json.RawMessage(`{"a":1}`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != "42" || got.ID != 42 || got.EventType != "author.created" {
+		t.Errorf("unexpected delivery: key %q, message %+v", key, got)
+	}
+}
+
+func TestWebhookSink_PublishFailsOnErrorStatus(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	sink := NewWebhookSink(srv.URL, srv.Client())
+
+	// Act
+	err := sink.Publish(context.Background(), Message{ID: 1})
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestFileSink_Publish(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	// Act
+	sink.Publish(context.Background(), Message{ID: 1, Payload: json.RawMessage(`{}`)})
+	sink.Publish(context.Background(), Message{ID: 2, Payload: json.RawMessage(`{}`)})
+	sink.Close()
+
+	// Assert
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"id":2`) {
+		t.Errorf("unexpected file contents:\n%s", data)
+	}
+}
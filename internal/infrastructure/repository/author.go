@@ -2,80 +2,371 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 	"github.com/seldomhappy/sqlc-test/tutorial"
 )
 
+// likeEscaper escapes LIKE wildcards so user input is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// DB is a connection pool, connection or transaction the repositories can
+// query and begin transactions on; *pgxpool.Pool is the usual one.
+type DB interface {
+	tutorial.DBTX
+	database.Beginner
+}
+
 // AuthorRepository implements the author.Repository interface using PostgreSQL.
+// All errors are translated into pkg/errors domain errors. Every statement
+// runs in a transaction scoped to the tenant of its context (see
+// database.InTenant), so row-level security limits it to that tenant's rows.
 type AuthorRepository struct {
-	queries *tutorial.Queries
+	db       DB
+	queries  *tutorial.Queries
+	replicas *database.ReplicaSet
 }
 
-// New creates a new AuthorRepository.
-func New(conn *pgx.Conn) *AuthorRepository {
+// New creates a new AuthorRepository. db is typically a *pgxpool.Pool, but a
+// single connection or a transaction is accepted too.
+func New(db DB) *AuthorRepository {
 	return &AuthorRepository{
-		queries: tutorial.New(conn),
+		db:      db,
+		queries: tutorial.New(db),
 	}
 }
 
+// NewWithReplicas creates an AuthorRepository that writes to db and serves
+// reads outside a transaction from replicas. Reads inside a transaction, and
+// the reads a write makes to explain a conflict, always use db.
+func NewWithReplicas(db DB, replicas *database.ReplicaSet) *AuthorRepository {
+	r := New(db)
+	r.replicas = replicas
+	return r
+}
+
+// write runs fn with queries for the primary, in the tenant's transaction.
+func (r *AuthorRepository) write(ctx context.Context, fn func(q *tutorial.Queries) error) error {
+	return database.InTenant(ctx, r.db, func(tx pgx.Tx) error {
+		return fn(r.queries.WithTx(tx))
+	})
+}
+
+// read is write for reads: outside a transaction it uses readDB.
+func (r *AuthorRepository) read(ctx context.Context, fn func(q *tutorial.Queries) error) error {
+	return database.InTenant(ctx, r.readDB(ctx), func(tx pgx.Tx) error {
+		return fn(r.queries.WithTx(tx))
+	})
+}
+
+// readDB returns the replica chosen for ctx, if replicas are configured.
+func (r *AuthorRepository) readDB(ctx context.Context) database.Beginner {
+	if r.replicas == nil {
+		return r.db
+	}
+	return r.replicas.Reader(ctx)
+}
+
 // GetAuthor retrieves a single author by ID.
 func (r *AuthorRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
-	a, err := r.queries.GetAuthor(ctx, id)
+	var a tutorial.Author
+	err := r.read(ctx, func(q *tutorial.Queries) (err error) {
+		a, err = q.GetAuthor(ctx, id)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
-	return &author.Author{
-		ID:   a.ID,
-		Name: a.Name,
-		Bio:  a.Bio,
-	}, nil
+	return toDomain(a), nil
+}
+
+// GetAuthorsByIDs retrieves the authors with the given IDs in one query,
+// in the order of ids, and reports the IDs that were not found.
+func (r *AuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	var authors []tutorial.Author
+	err := r.read(ctx, func(q *tutorial.Queries) (err error) {
+		authors, err = q.GetAuthorsByIDs(ctx, ids)
+		return err
+	})
+	if err != nil {
+		return nil, nil, translateError(err)
+	}
+	found, missing := author.MatchIDs(ids, toDomainList(authors))
+	return found, missing, nil
 }
 
 // ListAuthors retrieves all authors.
 func (r *AuthorRepository) ListAuthors(ctx context.Context) ([]*author.Author, error) {
-	authors, err := r.queries.ListAuthors(ctx)
+	var authors []tutorial.Author
+	err := r.read(ctx, func(q *tutorial.Queries) (err error) {
+		authors, err = q.ListAuthors(ctx)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
-	result := make([]*author.Author, len(authors))
-	for i, a := range authors {
-		result[i] = &author.Author{
-			ID:   a.ID,
-			Name: a.Name,
-			Bio:  a.Bio,
+	return toDomainList(authors), nil
+}
+
+// ListAuthorsPage retrieves up to params.Limit authors ordered by (name, id),
+// starting after params.After.
+func (r *AuthorRepository) ListAuthorsPage(ctx context.Context, params author.ListAuthorsPageParams) ([]*author.Author, error) {
+	arg := tutorial.ListAuthorsPageParams{
+		IncludeDeleted: params.IncludeDeleted,
+		PageSize:       params.Limit,
+	}
+	if params.After != nil {
+		arg.HasCursor = true
+		arg.AfterName = params.After.Name
+		arg.AfterID = params.After.ID
+	}
+	var authors []tutorial.Author
+	err := r.read(ctx, func(q *tutorial.Queries) (err error) {
+		authors, err = q.ListAuthorsPage(ctx, arg)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainList(authors), nil
+}
+
+// SearchAuthors retrieves authors matching params, best full-text matches first.
+func (r *AuthorRepository) SearchAuthors(ctx context.Context, params author.SearchAuthorsParams) ([]*author.Author, error) {
+	arg := tutorial.SearchAuthorsParams{
+		Query:       params.Query,
+		NamePrefix:  likeEscaper.Replace(params.NamePrefix),
+		HasBio:      params.HasBio,
+		ResultLimit: params.Limit,
+	}
+	var authors []tutorial.Author
+	err := r.read(ctx, func(q *tutorial.Queries) (err error) {
+		authors, err = q.SearchAuthors(ctx, arg)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainList(authors), nil
+}
+
+// streamAuthorsSQL selects every live author in ID order. It lives here
+// rather than in query.sql because sqlc always buffers :many results.
+const streamAuthorsSQL = `SELECT id, name, bio, external_id, deleted_at, version FROM authors
+WHERE deleted_at IS NULL
+ORDER BY id`
+
+// StreamAuthors calls fn for each live author in ID order without buffering
+// the result set. Errors from fn are returned unchanged.
+func (r *AuthorRepository) StreamAuthors(ctx context.Context, fn func(*author.Author) error) error {
+	var fnErr error
+	err := database.InTenant(ctx, r.readDB(ctx), func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, streamAuthorsSQL)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a tutorial.Author
+			if err := rows.Scan(&a.ID, &a.Name, &a.Bio, &a.ExternalID, &a.DeletedAt, &a.Version); err != nil {
+				return err
+			}
+			if fnErr = fn(toDomain(a)); fnErr != nil {
+				return fnErr
+			}
 		}
+		return rows.Err()
+	})
+	if fnErr != nil {
+		return fnErr
 	}
-	return result, nil
+	return translateError(err)
 }
 
 // CreateAuthor creates a new author.
 func (r *AuthorRepository) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
-	created, err := r.queries.CreateAuthor(ctx, tutorial.CreateAuthorParams{
-		Name: params.Name,
-		Bio:  params.Bio,
+	var created tutorial.Author
+	err := r.write(ctx, func(q *tutorial.Queries) (err error) {
+		created, err = q.CreateAuthor(ctx, tutorial.CreateAuthorParams{
+			Name: params.Name,
+			Bio:  params.Bio,
+		})
+		return err
 	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
-	return &author.Author{
-		ID:   created.ID,
-		Name: created.Name,
-		Bio:  created.Bio,
-	}, nil
+	return toDomain(created), nil
 }
 
-// UpdateAuthor updates an existing author.
+// UpsertAuthor creates or updates the author with params.ExternalID in a
+// single statement, so concurrent upserts of the same author cannot both
+// create it.
+func (r *AuthorRepository) UpsertAuthor(ctx context.Context, params author.UpsertAuthorParams) (*author.Author, bool, error) {
+	var row tutorial.UpsertAuthorRow
+	err := r.write(ctx, func(q *tutorial.Queries) (err error) {
+		row, err = q.UpsertAuthor(ctx, tutorial.UpsertAuthorParams{
+			ExternalID: pgtype.Text{String: params.ExternalID, Valid: true},
+			Name:       params.Name,
+			Bio:        params.Bio,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, false, translateError(err)
+	}
+	return toDomain(tutorial.Author{
+		ID:         row.ID,
+		Name:       row.Name,
+		Bio:        row.Bio,
+		ExternalID: row.ExternalID,
+		DeletedAt:  row.DeletedAt,
+		Version:    row.Version,
+	}), row.Inserted, nil
+}
+
+// BulkCreateAuthors inserts params with a single INSERT and returns the new
+// authors in params order. The statement is atomic, so a failing row (e.g. a
+// constraint violation) aborts the whole batch.
+func (r *AuthorRepository) BulkCreateAuthors(ctx context.Context, params []author.CreateAuthorParams) ([]*author.Author, error) {
+	arg := tutorial.BulkCreateAuthorsParams{
+		Names:   make([]string, len(params)),
+		Bios:    make([]string, len(params)),
+		HasBios: make([]bool, len(params)),
+	}
+	for i, p := range params {
+		arg.Names[i] = p.Name
+		arg.Bios[i] = p.Bio.String
+		arg.HasBios[i] = p.Bio.Valid
+	}
+	var rows []tutorial.BulkCreateAuthorsRow
+	err := r.write(ctx, func(q *tutorial.Queries) (err error) {
+		rows, err = q.BulkCreateAuthors(ctx, arg)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	authors := make([]*author.Author, len(rows))
+	for i, row := range rows {
+		authors[i] = toDomain(tutorial.Author(row))
+	}
+	return authors, nil
+}
+
+// UpdateAuthor updates an existing author if its version still matches.
 func (r *AuthorRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
-	return r.queries.UpdateAuthor(ctx, tutorial.UpdateAuthorParams{
-		ID:   params.ID,
-		Name: params.Name,
-		Bio:  params.Bio,
+	err := r.write(ctx, func(q *tutorial.Queries) error {
+		n, err := q.UpdateAuthor(ctx, tutorial.UpdateAuthorParams{
+			ID:              params.ID,
+			Name:            params.Name,
+			Bio:             params.Bio,
+			ExpectedVersion: params.Version,
+		})
+		if err == nil && n == 0 {
+			err = notFoundOrConflict(ctx, q, params.ID)
+		}
+		return err
 	})
+	return translateError(err)
 }
 
-// DeleteAuthor deletes an author by ID.
-func (r *AuthorRepository) DeleteAuthor(ctx context.Context, id int64) error {
-	return r.queries.DeleteAuthor(ctx, id)
+// PatchAuthor updates the fields set in params if the version still matches.
+func (r *AuthorRepository) PatchAuthor(ctx context.Context, params author.PatchAuthorParams) error {
+	err := r.write(ctx, func(q *tutorial.Queries) error {
+		n, err := q.PatchAuthor(ctx, tutorial.PatchAuthorParams{
+			ID:              params.ID,
+			Name:            pgtype.Text{String: params.Name.Value, Valid: params.Name.Set && !params.Name.Null},
+			SetBio:          params.Bio.Set,
+			Bio:             pgtype.Text{String: params.Bio.Value, Valid: !params.Bio.Null},
+			ExpectedVersion: params.Version,
+		})
+		if err == nil && n == 0 {
+			err = notFoundOrConflict(ctx, q, params.ID)
+		}
+		return err
+	})
+	return translateError(err)
+}
+
+// DeleteAuthor soft-deletes an author if its version still matches.
+func (r *AuthorRepository) DeleteAuthor(ctx context.Context, params author.DeleteAuthorParams) error {
+	err := r.write(ctx, func(q *tutorial.Queries) error {
+		n, err := q.DeleteAuthor(ctx, tutorial.DeleteAuthorParams{
+			ID:              params.ID,
+			ExpectedVersion: params.Version,
+		})
+		if err == nil && n == 0 {
+			err = notFoundOrConflict(ctx, q, params.ID)
+		}
+		return err
+	})
+	return translateError(err)
+}
+
+// RestoreAuthor undoes a soft delete. It returns errors.NotFoundError if no
+// soft-deleted author with the given ID exists.
+func (r *AuthorRepository) RestoreAuthor(ctx context.Context, id int64) error {
+	err := r.write(ctx, func(q *tutorial.Queries) error {
+		n, err := q.RestoreAuthor(ctx, id)
+		if err == nil && n == 0 {
+			err = apperrors.NotFoundError
+		}
+		return err
+	})
+	return translateError(err)
+}
+
+// PurgeDeletedAuthors permanently removes authors soft-deleted before
+// deletedBefore and returns how many were removed.
+func (r *AuthorRepository) PurgeDeletedAuthors(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var n int64
+	err := r.write(ctx, func(q *tutorial.Queries) (err error) {
+		n, err = q.PurgeDeletedAuthors(ctx, pgtype.Timestamptz{Time: deletedBefore, Valid: true})
+		return err
+	})
+	return n, translateError(err)
+}
+
+// notFoundOrConflict explains why a versioned write through q touched no
+// rows: either the author is gone or its version moved on.
+func notFoundOrConflict(ctx context.Context, q *tutorial.Queries, id int64) error {
+	_, err := q.GetAuthor(ctx, id)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return apperrors.NotFoundError
+	case err != nil:
+		return translateError(err)
+	default:
+		return apperrors.PreconditionFailedError
+	}
+}
+
+// toDomain converts a sqlc author row into the domain model.
+func toDomain(a tutorial.Author) *author.Author {
+	return &author.Author{
+		ID:         a.ID,
+		Name:       a.Name,
+		Bio:        a.Bio,
+		ExternalID: a.ExternalID,
+		DeletedAt:  a.DeletedAt,
+		Version:    a.Version,
+	}
+}
+
+// toDomainList converts sqlc author rows into domain models.
+func toDomainList(authors []tutorial.Author) []*author.Author {
+	result := make([]*author.Author, len(authors))
+	for i, a := range authors {
+		result[i] = toDomain(a)
+	}
+	return result
 }
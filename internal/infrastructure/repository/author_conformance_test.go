@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author/authortest"
+	"github.com/seldomhappy/sqlc-test/internal/domain/tenant"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/migrate"
+	"github.com/seldomhappy/sqlc-test/migrations"
+)
+
+// TestAuthorRepository_Conformance runs the shared repository suite against a
+// real database. It is skipped unless TEST_DATABASE_URL points at a
+// disposable database: the authors table is truncated before every subtest.
+func TestAuthorRepository_Conformance(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrate.New(pool, all).Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	authortest.RunRepositoryTests(t, func(t *testing.T) author.Repository {
+		if _, err := pool.Exec(ctx, "TRUNCATE authors RESTART IDENTITY CASCADE"); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		return tenantRepository{Repository: New(pool), tenant: "conformance"}
+	})
+}
+
+// tenantRepository scopes every call to tenant, as the HTTP layer does; row
+// level security hides every row from calls without one.
+type tenantRepository struct {
+	author.Repository
+	tenant string
+}
+
+func (r tenantRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
+	return r.Repository.GetAuthor(tenant.With(ctx, r.tenant), id)
+}
+
+func (r tenantRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	return r.Repository.GetAuthorsByIDs(tenant.With(ctx, r.tenant), ids)
+}
+
+func (r tenantRepository) ListAuthors(ctx context.Context) ([]*author.Author, error) {
+	return r.Repository.ListAuthors(tenant.With(ctx, r.tenant))
+}
+
+func (r tenantRepository) ListAuthorsPage(ctx context.Context, params author.ListAuthorsPageParams) ([]*author.Author, error) {
+	return r.Repository.ListAuthorsPage(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) SearchAuthors(ctx context.Context, params author.SearchAuthorsParams) ([]*author.Author, error) {
+	return r.Repository.SearchAuthors(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) StreamAuthors(ctx context.Context, fn func(*author.Author) error) error {
+	return r.Repository.StreamAuthors(tenant.With(ctx, r.tenant), fn)
+}
+
+func (r tenantRepository) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
+	return r.Repository.CreateAuthor(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) UpsertAuthor(ctx context.Context, params author.UpsertAuthorParams) (*author.Author, bool, error) {
+	return r.Repository.UpsertAuthor(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) BulkCreateAuthors(ctx context.Context, params []author.CreateAuthorParams) ([]*author.Author, error) {
+	return r.Repository.BulkCreateAuthors(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
+	return r.Repository.UpdateAuthor(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) PatchAuthor(ctx context.Context, params author.PatchAuthorParams) error {
+	return r.Repository.PatchAuthor(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) DeleteAuthor(ctx context.Context, params author.DeleteAuthorParams) error {
+	return r.Repository.DeleteAuthor(tenant.With(ctx, r.tenant), params)
+}
+
+func (r tenantRepository) RestoreAuthor(ctx context.Context, id int64) error {
+	return r.Repository.RestoreAuthor(tenant.With(ctx, r.tenant), id)
+}
+
+func (r tenantRepository) PurgeDeletedAuthors(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.Repository.PurgeDeletedAuthors(tenant.With(ctx, r.tenant), deletedBefore)
+}
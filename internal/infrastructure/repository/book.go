@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+	"github.com/seldomhappy/sqlc-test/tutorial"
+)
+
+// BookRepository implements the book.Repository interface using PostgreSQL.
+// All errors are translated into pkg/errors domain errors. Like
+// AuthorRepository it only sees the rows of the tenant of its context.
+type BookRepository struct {
+	db      DB
+	queries *tutorial.Queries
+}
+
+// NewBookRepository creates a new BookRepository.
+func NewBookRepository(db DB) *BookRepository {
+	return &BookRepository{
+		db:      db,
+		queries: tutorial.New(db),
+	}
+}
+
+// run runs fn in the tenant's transaction.
+func (r *BookRepository) run(ctx context.Context, fn func(q *tutorial.Queries) error) error {
+	return database.InTenant(ctx, r.db, func(tx pgx.Tx) error {
+		return fn(r.queries.WithTx(tx))
+	})
+}
+
+// GetBook retrieves a single book by ID.
+func (r *BookRepository) GetBook(ctx context.Context, id int64) (*book.Book, error) {
+	var b tutorial.Book
+	err := r.run(ctx, func(q *tutorial.Queries) (err error) {
+		b, err = q.GetBook(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainBook(b), nil
+}
+
+// ListBooks retrieves all books ordered by title.
+func (r *BookRepository) ListBooks(ctx context.Context) ([]*book.Book, error) {
+	var books []tutorial.Book
+	err := r.run(ctx, func(q *tutorial.Queries) (err error) {
+		books, err = q.ListBooks(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainBookList(books), nil
+}
+
+// ListBooksByAuthor retrieves an author's books ordered by publication date.
+func (r *BookRepository) ListBooksByAuthor(ctx context.Context, authorID int64) ([]*book.Book, error) {
+	var books []tutorial.Book
+	err := r.run(ctx, func(q *tutorial.Queries) (err error) {
+		books, err = q.ListBooksByAuthor(ctx, authorID)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainBookList(books), nil
+}
+
+// CreateBook creates a new book.
+func (r *BookRepository) CreateBook(ctx context.Context, params book.CreateBookParams) (*book.Book, error) {
+	var created tutorial.Book
+	err := r.run(ctx, func(q *tutorial.Queries) (err error) {
+		created, err = q.CreateBook(ctx, tutorial.CreateBookParams{
+			AuthorID:    params.AuthorID,
+			Title:       params.Title,
+			Isbn:        params.ISBN,
+			PublishedAt: params.PublishedAt,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainBook(created), nil
+}
+
+// UpdateBook updates an existing book.
+func (r *BookRepository) UpdateBook(ctx context.Context, params book.UpdateBookParams) error {
+	var n int64
+	err := r.run(ctx, func(q *tutorial.Queries) (err error) {
+		n, err = q.UpdateBook(ctx, tutorial.UpdateBookParams{
+			ID:          params.ID,
+			AuthorID:    params.AuthorID,
+			Title:       params.Title,
+			Isbn:        params.ISBN,
+			PublishedAt: params.PublishedAt,
+		})
+		return err
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	if n == 0 {
+		return apperrors.NotFoundError
+	}
+	return nil
+}
+
+// DeleteBook deletes a book by ID.
+func (r *BookRepository) DeleteBook(ctx context.Context, id int64) error {
+	var n int64
+	err := r.run(ctx, func(q *tutorial.Queries) (err error) {
+		n, err = q.DeleteBook(ctx, id)
+		return err
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	if n == 0 {
+		return apperrors.NotFoundError
+	}
+	return nil
+}
+
+// toDomainBook converts a sqlc book row into the domain model.
+func toDomainBook(b tutorial.Book) *book.Book {
+	return &book.Book{
+		ID:          b.ID,
+		AuthorID:    b.AuthorID,
+		Title:       b.Title,
+		ISBN:        b.Isbn,
+		PublishedAt: b.PublishedAt,
+	}
+}
+
+// toDomainBookList converts sqlc book rows into domain models.
+func toDomainBookList(books []tutorial.Book) []*book.Book {
+	result := make([]*book.Book, len(books))
+	for i, b := range books {
+		result[i] = toDomainBook(b)
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// PostgreSQL SQLSTATE codes translated into domain errors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+	pgStringTooLong       = "22001"
+)
+
+// translateError converts pgx errors into pkg/errors domain errors so callers
+// never need to know about the driver. Domain errors pass through unchanged.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.NewDomainError(apperrors.CodeNotFound, "resource not found", err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return apperrors.ConflictError("resource already exists", err)
+		case pgForeignKeyViolation:
+			return apperrors.ConflictError("referenced resource does not exist or is still referenced", err)
+		case pgCheckViolation, pgNotNullViolation, pgStringTooLong:
+			return apperrors.NewDomainError(apperrors.CodeValidation, "invalid field value", err)
+		}
+	}
+
+	return apperrors.DatabaseError(err)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{"no rows", pgx.ErrNoRows, apperrors.CodeNotFound},
+		{"wrapped no rows", fmt.Errorf("get: %w", pgx.ErrNoRows), apperrors.CodeNotFound},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, apperrors.CodeConflict},
+		{"foreign key violation", &pgconn.PgError{Code: "23503"}, apperrors.CodeConflict},
+		{"check violation", &pgconn.PgError{Code: "23514"}, apperrors.CodeValidation},
+		{"other pg error", &pgconn.PgError{Code: "57P01"}, apperrors.CodeDatabase},
+		{"connection error", errors.New("connection refused"), apperrors.CodeDatabase},
+		{"domain error", apperrors.PreconditionFailedError, apperrors.CodePreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var domainErr *apperrors.DomainError
+			if !errors.As(translateError(tt.err), &domainErr) {
+				t.Fatalf("expected DomainError, got %T", translateError(tt.err))
+			}
+			if domainErr.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, domainErr.Code)
+			}
+		})
+	}
+}
+
+func TestTranslateError_KeepsCause(t *testing.T) {
+	err := translateError(pgx.ErrNoRows)
+
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected errors.Is to match NotFoundError")
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected errors.Is to match pgx.ErrNoRows")
+	}
+	if translateError(nil) != nil {
+		t.Errorf("expected nil for nil error")
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/idempotency"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+	"github.com/seldomhappy/sqlc-test/tutorial"
+)
+
+// IdempotencyStore implements idempotency.Store on the idempotency_keys
+// table. Like AuthorRepository it only sees the keys of the tenant of its
+// context. Errors are translated into pkg/errors domain errors.
+type IdempotencyStore struct {
+	db      DB
+	queries *tutorial.Queries
+}
+
+// NewIdempotencyStore creates a new IdempotencyStore.
+func NewIdempotencyStore(db DB) *IdempotencyStore {
+	return &IdempotencyStore{
+		db:      db,
+		queries: tutorial.New(db),
+	}
+}
+
+// run runs fn in the tenant's transaction.
+func (s *IdempotencyStore) run(ctx context.Context, fn func(q *tutorial.Queries) error) error {
+	return database.InTenant(ctx, s.db, func(tx pgx.Tx) error {
+		return fn(s.queries.WithTx(tx))
+	})
+}
+
+// Reserve claims key, or returns the record of the unexpired request
+// holding it.
+func (s *IdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, now, expiresAt time.Time) (*idempotency.Record, bool, error) {
+	var row tutorial.IdempotencyKey
+	reserved := true
+	err := s.run(ctx, func(q *tutorial.Queries) (err error) {
+		row, err = q.ReserveIdempotencyKey(ctx, tutorial.ReserveIdempotencyKeyParams{
+			Key:         key,
+			Fingerprint: fingerprint,
+			Now:         pgtype.Timestamptz{Time: now, Valid: true},
+			ExpiresAt:   pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		})
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		// The conflicting row stays locked by the insert until the
+		// transaction ends, so it is still there.
+		reserved = false
+		row, err = q.GetIdempotencyKey(ctx, key)
+		return err
+	})
+	if err != nil {
+		return nil, false, translateError(err)
+	}
+	rec, err := toIdempotencyRecord(row)
+	if err != nil {
+		return nil, false, translateError(err)
+	}
+	return rec, reserved, nil
+}
+
+// Complete stores the response to the request holding key.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, resp idempotency.Response) error {
+	header, err := json.Marshal(resp.Header)
+	if err != nil {
+		return translateError(err)
+	}
+	err = s.run(ctx, func(q *tutorial.Queries) error {
+		return q.CompleteIdempotencyKey(ctx, tutorial.CompleteIdempotencyKeyParams{
+			Key:             key,
+			ResponseStatus:  pgtype.Int4{Int32: int32(resp.Status), Valid: true},
+			ResponseHeaders: header,
+			ResponseBody:    resp.Body,
+		})
+	})
+	return translateError(err)
+}
+
+// Release deletes key if its request has not completed.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	err := s.run(ctx, func(q *tutorial.Queries) error {
+		return q.ReleaseIdempotencyKey(ctx, key)
+	})
+	return translateError(err)
+}
+
+// DeleteExpired deletes the expired keys visible to the tenant of ctx.
+func (s *IdempotencyStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var n int64
+	err := s.run(ctx, func(q *tutorial.Queries) (err error) {
+		n, err = q.DeleteExpiredIdempotencyKeys(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+		return err
+	})
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return n, nil
+}
+
+// toIdempotencyRecord converts a sqlc row into an idempotency.Record.
+func toIdempotencyRecord(row tutorial.IdempotencyKey) (*idempotency.Record, error) {
+	rec := &idempotency.Record{
+		Key:         row.Key,
+		Fingerprint: row.Fingerprint,
+		ExpiresAt:   row.ExpiresAt.Time,
+	}
+	if row.ResponseStatus.Valid {
+		rec.Response = &idempotency.Response{
+			Status: int(row.ResponseStatus.Int32),
+			Body:   row.ResponseBody,
+		}
+		if err := json.Unmarshal(row.ResponseHeaders, &rec.Response.Header); err != nil {
+			return nil, err
+		}
+	}
+	return rec, nil
+}
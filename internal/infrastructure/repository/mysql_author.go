@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/mysqldb"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// MySQLAuthorRepository implements the author.Repository interface using
+// MySQL. Search uses a FULLTEXT index in boolean mode; the query is reduced
+// to its words, all of which must match.
+type MySQLAuthorRepository struct {
+	db      *sql.DB
+	queries *mysqldb.Queries
+}
+
+// NewMySQLAuthorRepository creates a new MySQLAuthorRepository. The schema in
+// mysqldb.Schema must already be applied.
+func NewMySQLAuthorRepository(db *sql.DB) *MySQLAuthorRepository {
+	return &MySQLAuthorRepository{
+		db:      db,
+		queries: mysqldb.New(db),
+	}
+}
+
+// GetAuthor retrieves a single author by ID.
+func (r *MySQLAuthorRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
+	a, err := r.queries.GetAuthor(ctx, id)
+	if err != nil {
+		return nil, translateMySQLError(err)
+	}
+	return mysqlToDomain(a), nil
+}
+
+// GetAuthorsByIDs retrieves the authors with the given IDs in one query,
+// in the order of ids, and reports the IDs that were not found.
+func (r *MySQLAuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	authors, err := r.queries.GetAuthorsByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, translateMySQLError(err)
+	}
+	found, missing := author.MatchIDs(ids, mysqlToDomainList(authors))
+	return found, missing, nil
+}
+
+// ListAuthors retrieves all authors.
+func (r *MySQLAuthorRepository) ListAuthors(ctx context.Context) ([]*author.Author, error) {
+	authors, err := r.queries.ListAuthors(ctx)
+	if err != nil {
+		return nil, translateMySQLError(err)
+	}
+	return mysqlToDomainList(authors), nil
+}
+
+// ListAuthorsPage retrieves up to params.Limit authors ordered by (name, id),
+// starting after params.After when set.
+func (r *MySQLAuthorRepository) ListAuthorsPage(ctx context.Context, params author.ListAuthorsPageParams) ([]*author.Author, error) {
+	arg := mysqldb.ListAuthorsPageParams{
+		IncludeDeleted: boolToInt64(params.IncludeDeleted),
+		Limit:          params.Limit,
+	}
+	if params.After != nil {
+		arg.HasCursor = 1
+		arg.AfterName = params.After.Name
+		arg.AfterID = params.After.ID
+	}
+	authors, err := r.queries.ListAuthorsPage(ctx, arg)
+	if err != nil {
+		return nil, translateMySQLError(err)
+	}
+	return mysqlToDomainList(authors), nil
+}
+
+// SearchAuthors finds authors whose bio matches params.Query, filtered by
+// name prefix and bio presence, best matches first.
+func (r *MySQLAuthorRepository) SearchAuthors(ctx context.Context, params author.SearchAuthorsParams) ([]*author.Author, error) {
+	var hasBio sql.NullInt64
+	if params.HasBio.Valid {
+		hasBio = sql.NullInt64{Int64: boolToInt64(params.HasBio.Bool), Valid: true}
+	}
+
+	var (
+		authors []mysqldb.Author
+		err     error
+	)
+	if strings.TrimSpace(params.Query) == "" {
+		authors, err = r.queries.FilterAuthors(ctx, mysqldb.FilterAuthorsParams{
+			NamePrefix: params.NamePrefix,
+			HasBio:     hasBio,
+			Limit:      params.Limit,
+		})
+	} else {
+		query := mysqlFTSQuery(params.Query)
+		if query == "" {
+			return []*author.Author{}, nil
+		}
+		authors, err = r.queries.SearchAuthors(ctx, mysqldb.SearchAuthorsParams{
+			Query:      query,
+			NamePrefix: params.NamePrefix,
+			HasBio:     hasBio,
+			Query_2:    query,
+			Limit:      params.Limit,
+		})
+	}
+	if err != nil {
+		return nil, translateMySQLError(err)
+	}
+	return mysqlToDomainList(authors), nil
+}
+
+// StreamAuthors calls fn for each live author in ID order without buffering
+// the result set.
+func (r *MySQLAuthorRepository) StreamAuthors(ctx context.Context, fn func(*author.Author) error) error {
+	rows, err := r.db.QueryContext(ctx, streamAuthorsSQL)
+	if err != nil {
+		return translateMySQLError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a mysqldb.Author
+		if err := rows.Scan(&a.ID, &a.Name, &a.Bio, &a.ExternalID, &a.DeletedAt, &a.Version); err != nil {
+			return translateMySQLError(err)
+		}
+		if err := fn(mysqlToDomain(a)); err != nil {
+			return err
+		}
+	}
+	return translateMySQLError(rows.Err())
+}
+
+// CreateAuthor creates a new author. MySQL has no RETURNING, so the row is
+// read back by its generated ID.
+func (r *MySQLAuthorRepository) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
+	id, err := r.queries.CreateAuthor(ctx, mysqldb.CreateAuthorParams{
+		Name: params.Name,
+		Bio:  toNullString(params.Bio),
+	})
+	if err != nil {
+		return nil, translateMySQLError(err)
+	}
+	created, err := r.queries.GetAuthor(ctx, id)
+	if err != nil {
+		return nil, translateMySQLError(err)
+	}
+	return mysqlToDomain(created), nil
+}
+
+// UpsertAuthor creates or updates the author with params.ExternalID and
+// reads it back in the same transaction.
+func (r *MySQLAuthorRepository) UpsertAuthor(ctx context.Context, params author.UpsertAuthorParams) (*author.Author, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, translateMySQLError(err)
+	}
+	defer tx.Rollback()
+
+	q := r.queries.WithTx(tx)
+	res, err := q.UpsertAuthor(ctx, mysqldb.UpsertAuthorParams{
+		ExternalID: sql.NullString{String: params.ExternalID, Valid: true},
+		Name:       params.Name,
+		Bio:        toNullString(params.Bio),
+	})
+	if err != nil {
+		return nil, false, translateMySQLError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, false, translateMySQLError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, translateMySQLError(err)
+	}
+	a, err := q.GetAuthor(ctx, id)
+	if err != nil {
+		return nil, false, translateMySQLError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, translateMySQLError(err)
+	}
+	return mysqlToDomain(a), n == 1, nil
+}
+
+// BulkCreateAuthors inserts params in a single transaction and reads the new
+// authors back in it.
+func (r *MySQLAuthorRepository) BulkCreateAuthors(ctx context.Context, params []author.CreateAuthorParams) ([]*author.Author, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateMySQLError(err)
+	}
+	defer tx.Rollback()
+
+	q := r.queries.WithTx(tx)
+	created := make([]*author.Author, 0, len(params))
+	for _, p := range params {
+		id, err := q.CreateAuthor(ctx, mysqldb.CreateAuthorParams{
+			Name: p.Name,
+			Bio:  toNullString(p.Bio),
+		})
+		if err != nil {
+			return nil, translateMySQLError(err)
+		}
+		a, err := q.GetAuthor(ctx, id)
+		if err != nil {
+			return nil, translateMySQLError(err)
+		}
+		created = append(created, mysqlToDomain(a))
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, translateMySQLError(err)
+	}
+	return created, nil
+}
+
+// UpdateAuthor updates an existing author if its version still matches.
+func (r *MySQLAuthorRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
+	n, err := r.queries.UpdateAuthor(ctx, mysqldb.UpdateAuthorParams{
+		ID:              params.ID,
+		Name:            params.Name,
+		Bio:             toNullString(params.Bio),
+		ExpectedVersion: params.Version,
+	})
+	if err != nil {
+		return translateMySQLError(err)
+	}
+	if n == 0 {
+		return r.notFoundOrConflict(ctx, params.ID)
+	}
+	return nil
+}
+
+// PatchAuthor updates the fields set in params if the version still matches.
+func (r *MySQLAuthorRepository) PatchAuthor(ctx context.Context, params author.PatchAuthorParams) error {
+	n, err := r.queries.PatchAuthor(ctx, mysqldb.PatchAuthorParams{
+		ID:              params.ID,
+		Name:            sql.NullString{String: params.Name.Value, Valid: params.Name.Set && !params.Name.Null},
+		SetBio:          boolToInt64(params.Bio.Set),
+		Bio:             sql.NullString{String: params.Bio.Value, Valid: !params.Bio.Null},
+		ExpectedVersion: params.Version,
+	})
+	if err != nil {
+		return translateMySQLError(err)
+	}
+	if n == 0 {
+		return r.notFoundOrConflict(ctx, params.ID)
+	}
+	return nil
+}
+
+// DeleteAuthor soft-deletes an author if its version still matches.
+func (r *MySQLAuthorRepository) DeleteAuthor(ctx context.Context, params author.DeleteAuthorParams) error {
+	n, err := r.queries.DeleteAuthor(ctx, mysqldb.DeleteAuthorParams{
+		ID:              params.ID,
+		DeletedAt:       sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ExpectedVersion: params.Version,
+	})
+	if err != nil {
+		return translateMySQLError(err)
+	}
+	if n == 0 {
+		return r.notFoundOrConflict(ctx, params.ID)
+	}
+	return nil
+}
+
+// RestoreAuthor clears the soft-delete marker of an author.
+func (r *MySQLAuthorRepository) RestoreAuthor(ctx context.Context, id int64) error {
+	n, err := r.queries.RestoreAuthor(ctx, id)
+	if err != nil {
+		return translateMySQLError(err)
+	}
+	if n == 0 {
+		return apperrors.NotFoundError
+	}
+	return nil
+}
+
+// PurgeDeletedAuthors permanently removes authors soft-deleted before
+// deletedBefore and returns how many were removed.
+func (r *MySQLAuthorRepository) PurgeDeletedAuthors(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	n, err := r.queries.PurgeDeletedAuthors(ctx, sql.NullTime{Time: deletedBefore.UTC(), Valid: true})
+	if err != nil {
+		return 0, translateMySQLError(err)
+	}
+	return n, nil
+}
+
+// notFoundOrConflict explains why an update or delete matched no rows: the
+// author either does not exist (or is deleted) or its version has moved on.
+func (r *MySQLAuthorRepository) notFoundOrConflict(ctx context.Context, id int64) error {
+	_, err := r.queries.GetAuthor(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFoundError
+	}
+	if err != nil {
+		return translateMySQLError(err)
+	}
+	return apperrors.PreconditionFailedError
+}
+
+// mysqlFTSQuery turns free text into a boolean-mode full-text query requiring
+// every word. Words are quoted so operators in user input are matched
+// literally.
+func mysqlFTSQuery(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = `+"` + w + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// boolToInt64 converts a flag to the 0/1 form the MySQL queries compare.
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// mysqlToDomain converts a mysqldb.Author to a domain Author.
+func mysqlToDomain(a mysqldb.Author) *author.Author {
+	return &author.Author{
+		ID:         a.ID,
+		Name:       a.Name,
+		Bio:        pgtype.Text{String: a.Bio.String, Valid: a.Bio.Valid},
+		ExternalID: pgtype.Text{String: a.ExternalID.String, Valid: a.ExternalID.Valid},
+		DeletedAt:  pgtype.Timestamptz{Time: a.DeletedAt.Time, Valid: a.DeletedAt.Valid},
+		Version:    a.Version,
+	}
+}
+
+// mysqlToDomainList converts a slice of mysqldb.Author to domain Authors.
+func mysqlToDomainList(authors []mysqldb.Author) []*author.Author {
+	result := make([]*author.Author, len(authors))
+	for i, a := range authors {
+		result[i] = mysqlToDomain(a)
+	}
+	return result
+}
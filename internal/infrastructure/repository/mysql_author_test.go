@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	stdsql "database/sql"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/seldomhappy/sqlc-test/config"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author/authortest"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+)
+
+// newMySQLRepository opens a MySQLAuthorRepository on a fresh database. It
+// uses TEST_MYSQL_DSN when set and otherwise starts an in-process
+// MySQL-compatible server, so the tests need no external services. That
+// server does not implement boolean-mode full-text search, so search tests
+// only run against a real MySQL.
+func newMySQLRepository(t *testing.T) *MySQLAuthorRepository {
+	t.Helper()
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		if strings.HasSuffix(t.Name(), "/Search") {
+			t.Skip("full-text search needs TEST_MYSQL_DSN")
+		}
+		dsn = startMySQLServer(t)
+	}
+	db, err := database.NewMySQL(context.Background(), &config.Config{DatabaseURL: dsn})
+	if err != nil {
+		t.Fatalf("open mysql: %v", err)
+	}
+	t.Cleanup(func() {
+		db.GetDB().Exec("DROP TABLE authors")
+		db.Close()
+	})
+	return NewMySQLAuthorRepository(db.GetDB())
+}
+
+// startMySQLServer serves an empty in-memory database on a free local port
+// and returns a DSN for it.
+func startMySQLServer(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	db := memory.NewDatabase("sqlc_test")
+	db.BaseDatabase.EnablePrimaryKeyIndexes()
+	pro := memory.NewDBProvider(db)
+	engine := sqle.NewDefault(pro)
+	s, err := server.NewServer(server.Config{Protocol: "tcp", Address: addr}, engine, sql.NewContext, memory.NewSessionBuilder(pro), nil)
+	if err != nil {
+		t.Fatalf("start mysql server: %v", err)
+	}
+	go s.Start()
+	t.Cleanup(func() { s.Close() })
+
+	return fmt.Sprintf("root@tcp(%s)/sqlc_test", addr)
+}
+
+func TestMySQLAuthorRepository_Conformance(t *testing.T) {
+	authortest.RunRepositoryTests(t, func(t *testing.T) author.Repository {
+		return newMySQLRepository(t)
+	})
+}
+
+func TestNewMySQL_UpgradesOlderSchema(t *testing.T) {
+	// Arrange: a database created before authors gained external_id.
+	ctx := context.Background()
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		dsn = startMySQLServer(t)
+	}
+	old, err := stdsql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("open mysql: %v", err)
+	}
+	t.Cleanup(func() { old.Exec("DROP TABLE authors"); old.Close() })
+	_, err = old.ExecContext(ctx, `
+		CREATE TABLE authors (
+		  id         BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		  name       VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+		  bio        TEXT,
+		  deleted_at DATETIME(6),
+		  version    INT          NOT NULL DEFAULT 1,
+		  INDEX authors_name_id_idx (name, id)
+		) DEFAULT CHARSET = utf8mb4`)
+	if err == nil {
+		_, err = old.ExecContext(ctx, "INSERT INTO authors (name) VALUES ('Old Author')")
+	}
+	if err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+
+	// Act: open it twice, as two starts would.
+	var db *database.MySQLDB
+	for i := 1; i <= 2; i++ {
+		db, err = database.NewMySQL(ctx, &config.Config{DatabaseURL: dsn})
+		if err != nil {
+			t.Fatalf("open mysql (start %d): %v", i, err)
+		}
+		t.Cleanup(db.Close)
+	}
+	repo := NewMySQLAuthorRepository(db.GetDB())
+
+	// Assert: the old row survives and external IDs work.
+	got, err := repo.GetAuthor(ctx, 1)
+	if err != nil || got.Name != "Old Author" {
+		t.Fatalf("GetAuthor(1) = %+v, %v; want Old Author", got, err)
+	}
+	if _, created, err := repo.UpsertAuthor(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "New"}); err != nil || !created {
+		t.Fatalf("UpsertAuthor = created %v, %v; want created", created, err)
+	}
+	if _, created, err := repo.UpsertAuthor(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "Renamed"}); err != nil || created {
+		t.Fatalf("UpsertAuthor again = created %v, %v; want updated", created, err)
+	}
+}
+
+func TestMySQLFTSQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"gardening", `+"gardening"`},
+		{`tomato -"x y"* @2`, `+"tomato" +"x" +"y" +"2"`},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := mysqlFTSQuery(tt.in); got != tt.want {
+			t.Errorf("mysqlFTSQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
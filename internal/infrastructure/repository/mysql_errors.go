@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// MySQL server error numbers translated by translateMySQLError.
+const (
+	mysqlErrDupEntry        = 1062
+	mysqlErrBadNull         = 1048
+	mysqlErrDataTooLong     = 1406
+	mysqlErrRowIsReferenced = 1451
+	mysqlErrNoReferencedRow = 1452
+	mysqlErrCheckConstraint = 3819
+)
+
+// translateMySQLError is the MySQL counterpart of translateError: it maps
+// database/sql and MySQL constraint errors to the same domain errors.
+func translateMySQLError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NewDomainError(apperrors.CodeNotFound, "resource not found", err)
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case mysqlErrDupEntry:
+			return apperrors.ConflictError("resource already exists", err)
+		case mysqlErrRowIsReferenced, mysqlErrNoReferencedRow:
+			return apperrors.ConflictError("referenced resource does not exist or is still referenced", err)
+		case mysqlErrBadNull, mysqlErrDataTooLong, mysqlErrCheckConstraint:
+			return apperrors.NewDomainError(apperrors.CodeValidation, "invalid field value", err)
+		}
+	}
+
+	return apperrors.DatabaseError(err)
+}
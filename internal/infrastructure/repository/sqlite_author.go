@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+	"github.com/seldomhappy/sqlc-test/sqlitedb"
+)
+
+// SQLiteAuthorRepository implements the author.Repository interface using
+// SQLite. Search uses an FTS5 index with the porter stemmer; the query is
+// reduced to its words, all of which must match.
+type SQLiteAuthorRepository struct {
+	db      *sql.DB
+	queries *sqlitedb.Queries
+}
+
+// NewSQLiteAuthorRepository creates a new SQLiteAuthorRepository. The schema
+// in sqlitedb.Schema must already be applied.
+func NewSQLiteAuthorRepository(db *sql.DB) *SQLiteAuthorRepository {
+	return &SQLiteAuthorRepository{
+		db:      db,
+		queries: sqlitedb.New(db),
+	}
+}
+
+// GetAuthor retrieves a single author by ID.
+func (r *SQLiteAuthorRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
+	a, err := r.queries.GetAuthor(ctx, id)
+	if err != nil {
+		return nil, translateSQLiteError(err)
+	}
+	return sqliteToDomain(a), nil
+}
+
+// GetAuthorsByIDs retrieves the authors with the given IDs in one query,
+// in the order of ids, and reports the IDs that were not found.
+func (r *SQLiteAuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	authors, err := r.queries.GetAuthorsByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, translateSQLiteError(err)
+	}
+	found, missing := author.MatchIDs(ids, sqliteToDomainList(authors))
+	return found, missing, nil
+}
+
+// ListAuthors retrieves all authors.
+func (r *SQLiteAuthorRepository) ListAuthors(ctx context.Context) ([]*author.Author, error) {
+	authors, err := r.queries.ListAuthors(ctx)
+	if err != nil {
+		return nil, translateSQLiteError(err)
+	}
+	return sqliteToDomainList(authors), nil
+}
+
+// ListAuthorsPage retrieves up to params.Limit authors ordered by (name, id),
+// starting after params.After when set.
+func (r *SQLiteAuthorRepository) ListAuthorsPage(ctx context.Context, params author.ListAuthorsPageParams) ([]*author.Author, error) {
+	arg := sqlitedb.ListAuthorsPageParams{
+		IncludeDeleted: params.IncludeDeleted,
+		PageSize:       int64(params.Limit),
+	}
+	if params.After != nil {
+		arg.HasCursor = true
+		arg.AfterName = params.After.Name
+		arg.AfterID = params.After.ID
+	}
+	authors, err := r.queries.ListAuthorsPage(ctx, arg)
+	if err != nil {
+		return nil, translateSQLiteError(err)
+	}
+	return sqliteToDomainList(authors), nil
+}
+
+// SearchAuthors finds authors whose bio matches params.Query, filtered by
+// name prefix and bio presence, best matches first.
+func (r *SQLiteAuthorRepository) SearchAuthors(ctx context.Context, params author.SearchAuthorsParams) ([]*author.Author, error) {
+	var hasBio any
+	if params.HasBio.Valid {
+		hasBio = params.HasBio.Bool
+	}
+
+	var (
+		authors []sqlitedb.Author
+		err     error
+	)
+	if strings.TrimSpace(params.Query) == "" {
+		authors, err = r.queries.FilterAuthors(ctx, sqlitedb.FilterAuthorsParams{
+			NamePrefix:  params.NamePrefix,
+			HasBio:      hasBio,
+			ResultLimit: int64(params.Limit),
+		})
+	} else {
+		query := ftsQuery(params.Query)
+		if query == "" {
+			return []*author.Author{}, nil
+		}
+		authors, err = r.queries.SearchAuthors(ctx, sqlitedb.SearchAuthorsParams{
+			Query:       query,
+			NamePrefix:  params.NamePrefix,
+			HasBio:      hasBio,
+			ResultLimit: int64(params.Limit),
+		})
+	}
+	if err != nil {
+		return nil, translateSQLiteError(err)
+	}
+	return sqliteToDomainList(authors), nil
+}
+
+// StreamAuthors calls fn for each live author in ID order without buffering
+// the result set.
+func (r *SQLiteAuthorRepository) StreamAuthors(ctx context.Context, fn func(*author.Author) error) error {
+	rows, err := r.db.QueryContext(ctx, streamAuthorsSQL)
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a sqlitedb.Author
+		if err := rows.Scan(&a.ID, &a.Name, &a.Bio, &a.ExternalID, &a.DeletedAt, &a.Version); err != nil {
+			return translateSQLiteError(err)
+		}
+		if err := fn(sqliteToDomain(a)); err != nil {
+			return err
+		}
+	}
+	return translateSQLiteError(rows.Err())
+}
+
+// CreateAuthor creates a new author.
+func (r *SQLiteAuthorRepository) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
+	created, err := r.queries.CreateAuthor(ctx, sqlitedb.CreateAuthorParams{
+		Name: params.Name,
+		Bio:  toNullString(params.Bio),
+	})
+	if err != nil {
+		return nil, translateSQLiteError(err)
+	}
+	return sqliteToDomain(created), nil
+}
+
+// UpsertAuthor creates or updates the author with params.ExternalID. SQLite
+// cannot tell which happened, but only a created row has version 1.
+func (r *SQLiteAuthorRepository) UpsertAuthor(ctx context.Context, params author.UpsertAuthorParams) (*author.Author, bool, error) {
+	a, err := r.queries.UpsertAuthor(ctx, sqlitedb.UpsertAuthorParams{
+		ExternalID: sql.NullString{String: params.ExternalID, Valid: true},
+		Name:       params.Name,
+		Bio:        toNullString(params.Bio),
+	})
+	if err != nil {
+		return nil, false, translateSQLiteError(err)
+	}
+	return sqliteToDomain(a), a.Version == 1, nil
+}
+
+// BulkCreateAuthors inserts params in a single transaction and returns the
+// new authors; SQLite has no COPY or arrays, but batching the inserts in one
+// transaction is nearly as fast.
+func (r *SQLiteAuthorRepository) BulkCreateAuthors(ctx context.Context, params []author.CreateAuthorParams) ([]*author.Author, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateSQLiteError(err)
+	}
+	defer tx.Rollback()
+
+	q := r.queries.WithTx(tx)
+	created := make([]*author.Author, 0, len(params))
+	for _, p := range params {
+		a, err := q.CreateAuthor(ctx, sqlitedb.CreateAuthorParams{
+			Name: p.Name,
+			Bio:  toNullString(p.Bio),
+		})
+		if err != nil {
+			return nil, translateSQLiteError(err)
+		}
+		created = append(created, sqliteToDomain(a))
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, translateSQLiteError(err)
+	}
+	return created, nil
+}
+
+// UpdateAuthor updates an existing author if its version still matches.
+func (r *SQLiteAuthorRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
+	n, err := r.queries.UpdateAuthor(ctx, sqlitedb.UpdateAuthorParams{
+		ID:              params.ID,
+		Name:            params.Name,
+		Bio:             toNullString(params.Bio),
+		ExpectedVersion: int64(params.Version),
+	})
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+	if n == 0 {
+		return r.notFoundOrConflict(ctx, params.ID)
+	}
+	return nil
+}
+
+// PatchAuthor updates the fields set in params if the version still matches.
+func (r *SQLiteAuthorRepository) PatchAuthor(ctx context.Context, params author.PatchAuthorParams) error {
+	n, err := r.queries.PatchAuthor(ctx, sqlitedb.PatchAuthorParams{
+		ID:              params.ID,
+		Name:            sql.NullString{String: params.Name.Value, Valid: params.Name.Set && !params.Name.Null},
+		SetBio:          params.Bio.Set,
+		Bio:             sql.NullString{String: params.Bio.Value, Valid: !params.Bio.Null},
+		ExpectedVersion: int64(params.Version),
+	})
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+	if n == 0 {
+		return r.notFoundOrConflict(ctx, params.ID)
+	}
+	return nil
+}
+
+// DeleteAuthor soft-deletes an author if its version still matches.
+func (r *SQLiteAuthorRepository) DeleteAuthor(ctx context.Context, params author.DeleteAuthorParams) error {
+	n, err := r.queries.DeleteAuthor(ctx, sqlitedb.DeleteAuthorParams{
+		ID:              params.ID,
+		DeletedAt:       sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ExpectedVersion: int64(params.Version),
+	})
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+	if n == 0 {
+		return r.notFoundOrConflict(ctx, params.ID)
+	}
+	return nil
+}
+
+// RestoreAuthor clears the soft-delete marker of an author.
+func (r *SQLiteAuthorRepository) RestoreAuthor(ctx context.Context, id int64) error {
+	n, err := r.queries.RestoreAuthor(ctx, id)
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+	if n == 0 {
+		return apperrors.NotFoundError
+	}
+	return nil
+}
+
+// PurgeDeletedAuthors permanently removes authors soft-deleted before
+// deletedBefore and returns how many were removed.
+func (r *SQLiteAuthorRepository) PurgeDeletedAuthors(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	n, err := r.queries.PurgeDeletedAuthors(ctx, sql.NullTime{Time: deletedBefore.UTC(), Valid: true})
+	if err != nil {
+		return 0, translateSQLiteError(err)
+	}
+	return n, nil
+}
+
+// notFoundOrConflict explains why an update or delete matched no rows: the
+// author either does not exist (or is deleted) or its version has moved on.
+func (r *SQLiteAuthorRepository) notFoundOrConflict(ctx context.Context, id int64) error {
+	_, err := r.queries.GetAuthor(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFoundError
+	}
+	if err != nil {
+		return translateSQLiteError(err)
+	}
+	return apperrors.PreconditionFailedError
+}
+
+// ftsQuery turns free text into an FTS5 query requiring every word. Words are
+// quoted so FTS5 operators in user input are matched literally.
+func ftsQuery(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = `"` + w + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// toNullString converts a domain text value to its database/sql form.
+func toNullString(t pgtype.Text) sql.NullString {
+	return sql.NullString{String: t.String, Valid: t.Valid}
+}
+
+// sqliteToDomain converts a sqlitedb.Author to a domain Author.
+func sqliteToDomain(a sqlitedb.Author) *author.Author {
+	return &author.Author{
+		ID:         a.ID,
+		Name:       a.Name,
+		Bio:        pgtype.Text{String: a.Bio.String, Valid: a.Bio.Valid},
+		ExternalID: pgtype.Text{String: a.ExternalID.String, Valid: a.ExternalID.Valid},
+		DeletedAt:  pgtype.Timestamptz{Time: a.DeletedAt.Time, Valid: a.DeletedAt.Valid},
+		Version:    int32(a.Version),
+	}
+}
+
+// sqliteToDomainList converts a slice of sqlitedb.Author to domain Authors.
+func sqliteToDomainList(authors []sqlitedb.Author) []*author.Author {
+	result := make([]*author.Author, len(authors))
+	for i, a := range authors {
+		result[i] = sqliteToDomain(a)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/config"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author/authortest"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/database"
+)
+
+func newSQLiteRepository(t *testing.T) *SQLiteAuthorRepository {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite",
+		filepath.Join(t.TempDir(), "test.db"))
+	db, err := database.NewSQLite(context.Background(), &config.Config{DatabaseURL: dsn})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(db.Close)
+	return NewSQLiteAuthorRepository(db.GetDB())
+}
+
+func TestSQLiteAuthorRepository_Conformance(t *testing.T) {
+	authortest.RunRepositoryTests(t, func(t *testing.T) author.Repository {
+		return newSQLiteRepository(t)
+	})
+}
+
+func TestNewSQLite_UpgradesOlderSchema(t *testing.T) {
+	// Arrange: a file created before authors gained external_id.
+	ctx := context.Background()
+	dsn := "file:" + filepath.Join(t.TempDir(), "old.db")
+	old, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	_, err = old.ExecContext(ctx, `
+		CREATE TABLE authors (
+		  id         INTEGER  PRIMARY KEY AUTOINCREMENT,
+		  name       TEXT     NOT NULL,
+		  bio        TEXT,
+		  deleted_at DATETIME,
+		  version    INTEGER  NOT NULL DEFAULT 1
+		);
+		INSERT INTO authors (name) VALUES ('Old Author');`)
+	old.Close()
+	if err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+
+	// Act: open it twice, as two starts would.
+	var db *database.SQLiteDB
+	for i := 1; i <= 2; i++ {
+		db, err = database.NewSQLite(ctx, &config.Config{DatabaseURL: dsn})
+		if err != nil {
+			t.Fatalf("open sqlite (start %d): %v", i, err)
+		}
+		t.Cleanup(db.Close)
+	}
+	repo := NewSQLiteAuthorRepository(db.GetDB())
+
+	// Assert: the old row survives and external IDs work.
+	got, err := repo.GetAuthor(ctx, 1)
+	if err != nil || got.Name != "Old Author" {
+		t.Fatalf("GetAuthor(1) = %+v, %v; want Old Author", got, err)
+	}
+	if _, created, err := repo.UpsertAuthor(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "New"}); err != nil || !created {
+		t.Fatalf("UpsertAuthor = created %v, %v; want created", created, err)
+	}
+	if _, created, err := repo.UpsertAuthor(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "Renamed"}); err != nil || created {
+		t.Fatalf("UpsertAuthor again = created %v, %v; want updated", created, err)
+	}
+}
+
+func TestFTSQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"gardening", `"gardening"`},
+		{`tomato OR "NEAR(x)" -y*`, `"tomato" "OR" "NEAR" "x" "y"`},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := ftsQuery(tt.in); got != tt.want {
+			t.Errorf("ftsQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// translateSQLiteError is the SQLite counterpart of translateError: it maps
+// database/sql and SQLite constraint errors to the same domain errors.
+func translateSQLiteError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NewDomainError(apperrors.CodeNotFound, "resource not found", err)
+	}
+
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		switch liteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return apperrors.ConflictError("resource already exists", err)
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return apperrors.ConflictError("referenced resource does not exist or is still referenced", err)
+		case sqlite3.SQLITE_CONSTRAINT_CHECK, sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+			return apperrors.NewDomainError(apperrors.CodeValidation, "invalid field value", err)
+		}
+	}
+
+	return apperrors.DatabaseError(err)
+}
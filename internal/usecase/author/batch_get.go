@@ -0,0 +1,41 @@
+package author
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// DefaultMaxBatchGet is the maximum number of IDs per batch get when none is
+// configured.
+const DefaultMaxBatchGet = 100
+
+// GetAuthorsByIDsUseCase retrieves many authors by ID in one round trip.
+type GetAuthorsByIDsUseCase struct {
+	repo     author.Repository
+	maxBatch int
+}
+
+// NewGetAuthorsByIDsUseCase creates a new GetAuthorsByIDsUseCase accepting at
+// most maxBatch IDs per call; a non-positive maxBatch uses
+// DefaultMaxBatchGet.
+func NewGetAuthorsByIDsUseCase(repo author.Repository, maxBatch int) *GetAuthorsByIDsUseCase {
+	if maxBatch <= 0 {
+		maxBatch = DefaultMaxBatchGet
+	}
+	return &GetAuthorsByIDsUseCase{repo: repo, maxBatch: maxBatch}
+}
+
+// Execute returns the authors with the given IDs in request order, each at
+// most once, and the IDs that do not exist or are soft-deleted.
+func (u *GetAuthorsByIDsUseCase) Execute(ctx context.Context, ids []int64) ([]*author.Author, []int64, error) {
+	switch {
+	case len(ids) == 0:
+		return nil, nil, apperrors.ValidationError("ids is required")
+	case len(ids) > u.maxBatch:
+		return nil, nil, apperrors.ValidationError(fmt.Sprintf("at most %d ids may be requested at once", u.maxBatch))
+	}
+	return u.repo.GetAuthorsByIDs(ctx, ids)
+}
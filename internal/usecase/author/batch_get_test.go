@@ -0,0 +1,58 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestGetAuthorsByIDsUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+	})
+	uc := NewGetAuthorsByIDsUseCase(repo, 10)
+
+	// Act
+	authors, missing, err := uc.Execute(context.Background(), []int64{2, 3, 1})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(authors) != 2 || authors[0].ID != 2 || authors[1].ID != 1 {
+		t.Errorf("expected authors 2 and 1 in request order, got %+v", authors)
+	}
+	if len(missing) != 1 || missing[0] != 3 {
+		t.Errorf("expected 3 to be missing, got %v", missing)
+	}
+}
+
+func TestGetAuthorsByIDsUseCase_ExecuteValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []int64
+	}{
+		{name: "no ids", ids: nil},
+		{name: "too many ids", ids: []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			uc := NewGetAuthorsByIDsUseCase(newTestRepository(nil), 2)
+
+			// Act
+			_, _, err := uc.Execute(context.Background(), tt.ids)
+
+			// Assert
+			if !errors.Is(err, apperrors.ValidationError("")) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
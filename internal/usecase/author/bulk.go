@@ -0,0 +1,117 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// BulkMode controls how BulkCreateAuthorsUseCase handles invalid rows.
+type BulkMode string
+
+const (
+	// BulkModeAllOrNothing rejects the whole batch if any row is invalid.
+	BulkModeAllOrNothing BulkMode = "all_or_nothing"
+	// BulkModeBestEffort inserts the valid rows and reports the invalid ones.
+	// Only validation errors are reported per row; a row the database
+	// rejects still fails the whole batch.
+	BulkModeBestEffort BulkMode = "best_effort"
+)
+
+// ParseBulkMode parses s into a BulkMode. An empty string selects
+// BulkModeAllOrNothing.
+func ParseBulkMode(s string) (BulkMode, error) {
+	switch BulkMode(s) {
+	case "", BulkModeAllOrNothing:
+		return BulkModeAllOrNothing, nil
+	case BulkModeBestEffort:
+		return BulkModeBestEffort, nil
+	}
+	return "", apperrors.ValidationError(fmt.Sprintf("invalid mode %q", s))
+}
+
+// BulkRowError reports why the row at Index was rejected.
+type BulkRowError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// BulkCreateAuthorsResult is the outcome of a bulk import.
+type BulkCreateAuthorsResult struct {
+	Inserted int64
+	Errors   []BulkRowError
+}
+
+// BulkCreateAuthorsUseCase validates and inserts many authors at once.
+type BulkCreateAuthorsUseCase struct {
+	repo   author.Repository
+	tx     transaction.Manager
+	events author.EventRecorder
+}
+
+// NewBulkCreateAuthorsUseCase creates a new BulkCreateAuthorsUseCase.
+func NewBulkCreateAuthorsUseCase(repo author.Repository, tx transaction.Manager, events author.EventRecorder) *BulkCreateAuthorsUseCase {
+	return &BulkCreateAuthorsUseCase{repo: repo, tx: tx, events: events}
+}
+
+// Execute validates every row, inserts the valid ones and records an
+// author.created event for each in the same transaction. In
+// BulkModeAllOrNothing any invalid row aborts the import with a validation
+// error; the returned result still lists the per-row errors. Database errors
+// always abort the whole batch.
+func (u *BulkCreateAuthorsUseCase) Execute(ctx context.Context, rows []author.CreateAuthorParams, mode BulkMode) (*BulkCreateAuthorsResult, error) {
+	if len(rows) == 0 {
+		return nil, apperrors.ValidationError("no authors to import")
+	}
+
+	result := &BulkCreateAuthorsResult{}
+	valid := make([]author.CreateAuthorParams, 0, len(rows))
+	for i, row := range rows {
+		if err := validateCreateAuthor(row); err != nil {
+			result.Errors = append(result.Errors, BulkRowError{Index: i, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	if len(result.Errors) > 0 && mode != BulkModeBestEffort {
+		return result, apperrors.ValidationError(fmt.Sprintf("%d of %d rows are invalid", len(result.Errors), len(rows)))
+	}
+	if len(valid) == 0 {
+		return result, nil
+	}
+
+	err := u.tx.RunInTx(ctx, func(ctx context.Context) error {
+		created, err := u.repo.BulkCreateAuthors(ctx, valid)
+		if err != nil {
+			return err
+		}
+		for _, a := range created {
+			if err := u.events.RecordEvent(ctx, author.NewEvent(author.EventCreated, a.ID, a)); err != nil {
+				return err
+			}
+		}
+		result.Inserted = int64(len(created))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateCreateAuthor reports the first problem with params, if any.
+func validateCreateAuthor(params author.CreateAuthorParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.ContainsRune(params.Name, 0) || strings.ContainsRune(params.Bio.String, 0) {
+		return errors.New("text must not contain NUL bytes")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestBulkCreateAuthorsUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	events := &eventLog{}
+	uc := NewBulkCreateAuthorsUseCase(repo, testTx, events)
+	rows := []author.CreateAuthorParams{{Name: "Alice"}, {Name: "Bob"}}
+
+	// Act
+	result, err := uc.Execute(context.Background(), rows, BulkModeAllOrNothing)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Inserted != 2 || len(result.Errors) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if stored, _ := repo.ListAuthors(context.Background()); len(stored) != 2 {
+		t.Errorf("expected 2 authors stored, got %d", len(stored))
+	}
+	if len(events.events) != 2 || events.events[0].Type != author.EventCreated || events.events[1].Author.Name != "Bob" {
+		t.Errorf("expected a created event per author, got %+v", events.events)
+	}
+}
+
+func TestBulkCreateAuthorsUseCase_ExecuteAllOrNothingRejectsBatch(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	uc := NewBulkCreateAuthorsUseCase(repo, testTx, &eventLog{})
+	rows := []author.CreateAuthorParams{{Name: "Alice"}, {Name: "  "}}
+
+	// Act
+	result, err := uc.Execute(context.Background(), rows, BulkModeAllOrNothing)
+
+	// Assert
+	if !errors.Is(err, apperrors.ValidationError("")) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 1 {
+		t.Errorf("expected row 1 to be reported, got %+v", result.Errors)
+	}
+	if stored, _ := repo.ListAuthors(context.Background()); len(stored) != 0 {
+		t.Errorf("expected nothing stored, got %d authors", len(stored))
+	}
+}
+
+func TestBulkCreateAuthorsUseCase_ExecuteBestEffort(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	uc := NewBulkCreateAuthorsUseCase(repo, testTx, &eventLog{})
+	rows := []author.CreateAuthorParams{{Name: ""}, {Name: "Alice"}, {Name: "Bad\x00Name"}}
+
+	// Act
+	result, err := uc.Execute(context.Background(), rows, BulkModeBestEffort)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Inserted != 1 {
+		t.Errorf("expected 1 inserted, got %d", result.Inserted)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Index != 0 || result.Errors[1].Index != 2 {
+		t.Errorf("expected rows 0 and 2 to be reported, got %+v", result.Errors)
+	}
+}
+
+func TestBulkCreateAuthorsUseCase_ExecuteEmpty(t *testing.T) {
+	// Arrange
+	uc := NewBulkCreateAuthorsUseCase(newTestRepository(nil), testTx, &eventLog{})
+
+	// Act
+	_, err := uc.Execute(context.Background(), nil, BulkModeBestEffort)
+
+	// Assert
+	if !errors.Is(err, apperrors.ValidationError("")) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestParseBulkMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    BulkMode
+		wantErr bool
+	}{
+		{"", BulkModeAllOrNothing, false},
+		{"all_or_nothing", BulkModeAllOrNothing, false},
+		{"best_effort", BulkModeBestEffort, false},
+		{"sometimes", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBulkMode(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBulkMode(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
@@ -4,19 +4,35 @@ import (
 	"context"
 
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
 )
 
 // CreateAuthorUseCase creates a new author.
 type CreateAuthorUseCase struct {
-	repo author.Repository
+	repo   author.Repository
+	tx     transaction.Manager
+	events author.EventRecorder
 }
 
 // NewCreateAuthorUseCase creates a new CreateAuthorUseCase.
-func NewCreateAuthorUseCase(repo author.Repository) *CreateAuthorUseCase {
-	return &CreateAuthorUseCase{repo: repo}
+func NewCreateAuthorUseCase(repo author.Repository, tx transaction.Manager, events author.EventRecorder) *CreateAuthorUseCase {
+	return &CreateAuthorUseCase{repo: repo, tx: tx, events: events}
 }
 
-// Execute creates a new author.
+// Execute creates a new author and records an author.created event in the
+// same transaction.
 func (u *CreateAuthorUseCase) Execute(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
-	return u.repo.CreateAuthor(ctx, params)
+	var created *author.Author
+	err := u.tx.RunInTx(ctx, func(ctx context.Context) error {
+		a, err := u.repo.CreateAuthor(ctx, params)
+		if err != nil {
+			return err
+		}
+		created = a
+		return u.events.RecordEvent(ctx, author.NewEvent(author.EventCreated, a.ID, a))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
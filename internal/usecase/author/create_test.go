@@ -10,10 +10,8 @@ import (
 
 func TestCreateAuthorUseCase_Execute(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{},
-	}
-	uc := NewCreateAuthorUseCase(mockRepo)
+	repo := newTestRepository(nil)
+	uc := NewCreateAuthorUseCase(repo, testTx, &eventLog{})
 	params := author.CreateAuthorParams{
 		Name: "Charlie",
 		Bio:  pgtype.Text{String: "New author", Valid: true},
@@ -39,10 +37,8 @@ func TestCreateAuthorUseCase_Execute(t *testing.T) {
 
 func TestCreateAuthorUseCase_ExecuteEmptyName(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{},
-	}
-	uc := NewCreateAuthorUseCase(mockRepo)
+	repo := newTestRepository(nil)
+	uc := NewCreateAuthorUseCase(repo, testTx, &eventLog{})
 	params := author.CreateAuthorParams{
 		Name: "",
 		Bio:  pgtype.Text{String: "", Valid: false},
@@ -60,3 +56,25 @@ func TestCreateAuthorUseCase_ExecuteEmptyName(t *testing.T) {
 		t.Errorf("expected empty name, got '%s'", created.Name)
 	}
 }
+
+func TestCreateAuthorUseCase_ExecuteRecordsEvent(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	events := &eventLog{}
+	uc := NewCreateAuthorUseCase(repo, testTx, events)
+
+	// Act
+	created, err := uc.Execute(context.Background(), author.CreateAuthorParams{Name: "Charlie"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events.events))
+	}
+	e := events.events[0]
+	if e.Type != author.EventCreated || e.AuthorID != created.ID || e.Author.Name != "Charlie" {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
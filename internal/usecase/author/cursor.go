@@ -0,0 +1,37 @@
+package author
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+)
+
+// cursorPayload is the JSON shape hidden inside an opaque page cursor.
+type cursorPayload struct {
+	Name string `json:"n"`
+	ID   int64  `json:"i"`
+}
+
+// encodeCursor turns c into an opaque, URL-safe token.
+func encodeCursor(c author.Cursor) string {
+	b, _ := json.Marshal(cursorPayload{Name: c.Name, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor parses a token produced by encodeCursor.
+func decodeCursor(token string) (*author.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var p cursorPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	if p.ID <= 0 {
+		return nil, errors.New("cursor id must be positive")
+	}
+	return &author.Cursor{Name: p.Name, ID: p.ID}, nil
+}
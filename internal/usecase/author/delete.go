@@ -4,19 +4,28 @@ import (
 	"context"
 
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
 )
 
 // DeleteAuthorUseCase deletes an author.
 type DeleteAuthorUseCase struct {
-	repo author.Repository
+	repo   author.Repository
+	tx     transaction.Manager
+	events author.EventRecorder
 }
 
 // NewDeleteAuthorUseCase creates a new DeleteAuthorUseCase.
-func NewDeleteAuthorUseCase(repo author.Repository) *DeleteAuthorUseCase {
-	return &DeleteAuthorUseCase{repo: repo}
+func NewDeleteAuthorUseCase(repo author.Repository, tx transaction.Manager, events author.EventRecorder) *DeleteAuthorUseCase {
+	return &DeleteAuthorUseCase{repo: repo, tx: tx, events: events}
 }
 
-// Execute deletes an author by ID.
-func (u *DeleteAuthorUseCase) Execute(ctx context.Context, id int64) error {
-	return u.repo.DeleteAuthor(ctx, id)
+// Execute deletes an author, provided params.Version is still current, and
+// records an author.deleted event in the same transaction.
+func (u *DeleteAuthorUseCase) Execute(ctx context.Context, params author.DeleteAuthorParams) error {
+	return u.tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := u.repo.DeleteAuthor(ctx, params); err != nil {
+			return err
+		}
+		return u.events.RecordEvent(ctx, author.NewEvent(author.EventDeleted, params.ID, nil))
+	})
 }
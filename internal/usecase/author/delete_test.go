@@ -2,67 +2,86 @@ package author
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 )
 
 func TestDeleteAuthorUseCase_Execute(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{
-			{
-				ID:   1,
-				Name: "Alice",
-				Bio:  pgtype.Text{String: "Author 1", Valid: true},
-			},
-			{
-				ID:   2,
-				Name: "Bob",
-				Bio:  pgtype.Text{String: "Author 2", Valid: true},
-			},
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Author 1", Valid: true},
 		},
-	}
-	uc := NewDeleteAuthorUseCase(mockRepo)
+		{
+			ID:   2,
+			Name: "Bob",
+			Bio:  pgtype.Text{String: "Author 2", Valid: true},
+		},
+	})
+	uc := NewDeleteAuthorUseCase(repo, testTx, &eventLog{})
 
 	// Act
-	err := uc.Execute(context.Background(), 1)
+	err := uc.Execute(context.Background(), author.DeleteAuthorParams{ID: 1})
 
 	// Assert
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(mockRepo.authors) != 1 {
-		t.Errorf("expected 1 author, got %d", len(mockRepo.authors))
+	remaining, _ := repo.ListAuthors(context.Background())
+	if len(remaining) != 1 {
+		t.Fatalf("expected 1 author, got %d", len(remaining))
 	}
-	if mockRepo.authors[0].ID != 2 {
-		t.Errorf("expected remaining author with ID 2, got %d", mockRepo.authors[0].ID)
+	if remaining[0].ID != 2 {
+		t.Errorf("expected remaining author with ID 2, got %d", remaining[0].ID)
 	}
 }
 
 func TestDeleteAuthorUseCase_ExecuteNotFound(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{
-			{
-				ID:   1,
-				Name: "Alice",
-				Bio:  pgtype.Text{String: "Author 1", Valid: true},
-			},
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Author 1", Valid: true},
 		},
+	})
+	uc := NewDeleteAuthorUseCase(repo, testTx, &eventLog{})
+
+	// Act
+	err := uc.Execute(context.Background(), author.DeleteAuthorParams{ID: 999})
+
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Fatalf("expected not found error, got %v", err)
 	}
-	uc := NewDeleteAuthorUseCase(mockRepo)
+	if remaining, _ := repo.ListAuthors(context.Background()); len(remaining) != 1 {
+		t.Errorf("expected 1 author, got %d", len(remaining))
+	}
+}
+
+func TestDeleteAuthorUseCase_ExecuteRecordsEvent(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{{ID: 1, Name: "Alice"}})
+	events := &eventLog{}
+	uc := NewDeleteAuthorUseCase(repo, testTx, events)
 
 	// Act
-	err := uc.Execute(context.Background(), 999)
+	err := uc.Execute(context.Background(), author.DeleteAuthorParams{ID: 1})
 
 	// Assert
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	// Mock repo silently ignores non-existent authors
-	if len(mockRepo.authors) != 1 {
-		t.Errorf("expected 1 author, got %d", len(mockRepo.authors))
+	if len(events.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events.events))
+	}
+	if e := events.events[0]; e.Type != author.EventDeleted || e.AuthorID != 1 || e.Author != nil {
+		t.Errorf("unexpected event %+v", e)
 	}
 }
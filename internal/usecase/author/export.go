@@ -0,0 +1,127 @@
+package author
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// ExportFormat selects the encoding used by ExportAuthorsUseCase.
+type ExportFormat string
+
+const (
+	// ExportFormatNDJSON writes one JSON object per line.
+	ExportFormatNDJSON ExportFormat = "ndjson"
+	// ExportFormatCSV writes a header row followed by one row per author.
+	// bio is empty both for a NULL and an empty bio; has_bio ("true" or
+	// "false") tells them apart.
+	ExportFormatCSV ExportFormat = "csv"
+)
+
+// exportFlushEvery is the number of rows written between flushes.
+const exportFlushEvery = 500
+
+// ParseExportFormat parses s into an ExportFormat. An empty string selects
+// ExportFormatNDJSON.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(s) {
+	case "", ExportFormatNDJSON:
+		return ExportFormatNDJSON, nil
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	}
+	return "", apperrors.ValidationError(fmt.Sprintf("invalid format %q", s))
+}
+
+// Flusher is implemented by writers that can push buffered data downstream,
+// such as a gzip writer wrapping an HTTP response.
+type Flusher interface {
+	Flush() error
+}
+
+// ExportAuthorsUseCase streams every live author to a writer.
+type ExportAuthorsUseCase struct {
+	repo author.Repository
+}
+
+// NewExportAuthorsUseCase creates a new ExportAuthorsUseCase.
+func NewExportAuthorsUseCase(repo author.Repository) *ExportAuthorsUseCase {
+	return &ExportAuthorsUseCase{repo: repo}
+}
+
+// Execute writes all live authors to w in the given format and returns the
+// number of authors written. Memory use is constant: rows are encoded as they
+// are read and flushed every few hundred rows, including w itself when it
+// implements Flusher.
+func (u *ExportAuthorsUseCase) Execute(ctx context.Context, w io.Writer, format ExportFormat) (int64, error) {
+	bw := bufio.NewWriter(w)
+	flush := func() error {
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(Flusher); ok {
+			return f.Flush()
+		}
+		return nil
+	}
+
+	var (
+		encode       func(*author.Author) error
+		flushEncoder func() error
+	)
+	switch format {
+	case ExportFormatNDJSON:
+		enc := json.NewEncoder(bw)
+		encode = func(a *author.Author) error { return enc.Encode(a) }
+		flushEncoder = func() error { return nil }
+	case ExportFormatCSV:
+		cw := csv.NewWriter(bw)
+		if err := cw.Write([]string{"id", "name", "bio", "has_bio", "version"}); err != nil {
+			return 0, err
+		}
+		encode = func(a *author.Author) error {
+			return cw.Write([]string{
+				strconv.FormatInt(a.ID, 10),
+				a.Name,
+				a.Bio.String,
+				strconv.FormatBool(a.Bio.Valid),
+				strconv.FormatInt(int64(a.Version), 10),
+			})
+		}
+		flushEncoder = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		return 0, apperrors.ValidationError(fmt.Sprintf("invalid format %q", format))
+	}
+
+	var n int64
+	err := u.repo.StreamAuthors(ctx, func(a *author.Author) error {
+		if err := encode(a); err != nil {
+			return err
+		}
+		n++
+		if n%exportFlushEvery == 0 {
+			if err := flushEncoder(); err != nil {
+				return err
+			}
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	if err := flushEncoder(); err != nil {
+		return n, err
+	}
+	return n, flush()
+}
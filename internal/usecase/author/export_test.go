@@ -0,0 +1,124 @@
+package author
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// countingFlusher records how often the use case flushes it.
+type countingFlusher struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (c *countingFlusher) Flush() error {
+	c.flushes++
+	return nil
+}
+
+func TestExportAuthorsUseCase_ExecuteCSV(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{ID: 1, Name: "Alice", Bio: pgtype.Text{String: "Writes, a lot", Valid: true}, Version: 2},
+		{ID: 2, Name: "Bob", Version: 1},
+		{ID: 3, Name: "Carol", Bio: pgtype.Text{Valid: true}, Version: 1},
+	})
+	uc := NewExportAuthorsUseCase(repo)
+	var buf bytes.Buffer
+
+	// Act
+	n, err := uc.Execute(context.Background(), &buf, ExportFormatCSV)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 rows, got %d", n)
+	}
+	want := "id,name,bio,has_bio,version\n1,Alice,\"Writes, a lot\",true,2\n2,Bob,,false,1\n3,Carol,,true,1\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestExportAuthorsUseCase_ExecuteNDJSON(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}})
+	uc := NewExportAuthorsUseCase(repo)
+	var buf bytes.Buffer
+
+	// Act
+	_, err := uc.Execute(context.Background(), &buf, ExportFormatNDJSON)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"Name":"Bob"`) {
+		t.Errorf("unexpected NDJSON:\n%s", buf.String())
+	}
+}
+
+func TestExportAuthorsUseCase_ExecuteFlushesPeriodically(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	for i := 0; i < 2*exportFlushEvery+1; i++ {
+		repo.SeedAuthors(&author.Author{Name: fmt.Sprintf("Author %d", i)})
+	}
+	uc := NewExportAuthorsUseCase(repo)
+	w := &countingFlusher{}
+
+	// Act
+	_, err := uc.Execute(context.Background(), w, ExportFormatNDJSON)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.flushes != 3 {
+		t.Errorf("expected 3 flushes, got %d", w.flushes)
+	}
+}
+
+func TestExportAuthorsUseCase_ExecuteRepositoryError(t *testing.T) {
+	// Arrange
+	uc := NewExportAuthorsUseCase(&failingRepository{err: apperrors.DatabaseError(errors.New("boom"))})
+	var buf bytes.Buffer
+
+	// Act
+	_, err := uc.Execute(context.Background(), &buf, ExportFormatCSV)
+
+	// Assert
+	if !errors.Is(err, apperrors.DatabaseError(nil)) {
+		t.Errorf("expected database error, got %v", err)
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ExportFormat
+		wantErr bool
+	}{
+		{"", ExportFormatNDJSON, false},
+		{"ndjson", ExportFormatNDJSON, false},
+		{"csv", ExportFormatCSV, false},
+		{"xml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseExportFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseExportFormat(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
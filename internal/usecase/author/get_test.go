@@ -2,24 +2,24 @@ package author
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 )
 
 func TestGetAuthorUseCase_Execute(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{
-			{
-				ID:   1,
-				Name: "Alice",
-				Bio:  pgtype.Text{String: "Author 1", Valid: true},
-			},
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Author 1", Valid: true},
 		},
-	}
-	uc := NewGetAuthorUseCase(mockRepo)
+	})
+	uc := NewGetAuthorUseCase(repo)
 
 	// Act
 	a, err := uc.Execute(context.Background(), 1)
@@ -41,17 +41,15 @@ func TestGetAuthorUseCase_Execute(t *testing.T) {
 
 func TestGetAuthorUseCase_ExecuteNotFound(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{},
-	}
-	uc := NewGetAuthorUseCase(mockRepo)
+	repo := newTestRepository(nil)
+	uc := NewGetAuthorUseCase(repo)
 
 	// Act
 	a, err := uc.Execute(context.Background(), 999)
 
 	// Assert
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Fatalf("expected not found error, got %v", err)
 	}
 	if a != nil {
 		t.Errorf("expected nil, got author with ID %d", a.ID)
@@ -4,9 +4,30 @@ import (
 	"context"
 
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 )
 
-// ListAuthorsUseCase retrieves all authors.
+// Page size bounds for ListAuthorsUseCase.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// ListAuthorsParams holds the pagination input for ListAuthorsUseCase.
+// Cursor is the opaque token returned as NextCursor by a previous call.
+type ListAuthorsParams struct {
+	Cursor         string
+	Limit          int
+	IncludeDeleted bool
+}
+
+// ListAuthorsResult is a page of authors. NextCursor is empty on the last page.
+type ListAuthorsResult struct {
+	Authors    []*author.Author
+	NextCursor string
+}
+
+// ListAuthorsUseCase retrieves authors one page at a time.
 type ListAuthorsUseCase struct {
 	repo author.Repository
 }
@@ -16,7 +37,40 @@ func NewListAuthorsUseCase(repo author.Repository) *ListAuthorsUseCase {
 	return &ListAuthorsUseCase{repo: repo}
 }
 
-// Execute retrieves all authors.
-func (u *ListAuthorsUseCase) Execute(ctx context.Context) ([]*author.Author, error) {
-	return u.repo.ListAuthors(ctx)
+// Execute retrieves the page of authors following params.Cursor.
+func (u *ListAuthorsUseCase) Execute(ctx context.Context, params ListAuthorsParams) (*ListAuthorsResult, error) {
+	limit := params.Limit
+	switch {
+	case limit == 0:
+		limit = DefaultPageSize
+	case limit < 0 || limit > MaxPageSize:
+		return nil, apperrors.ValidationError("limit must be between 1 and 200")
+	}
+
+	var after *author.Cursor
+	if params.Cursor != "" {
+		c, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, apperrors.ValidationError("invalid cursor")
+		}
+		after = c
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	authors, err := u.repo.ListAuthorsPage(ctx, author.ListAuthorsPageParams{
+		After:          after,
+		Limit:          int32(limit + 1),
+		IncludeDeleted: params.IncludeDeleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListAuthorsResult{Authors: authors}
+	if len(authors) > limit {
+		result.Authors = authors[:limit]
+		last := result.Authors[limit-1]
+		result.NextCursor = encodeCursor(author.Cursor{Name: last.Name, ID: last.ID})
+	}
+	return result, nil
 }
@@ -2,124 +2,147 @@ package author
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 )
 
-type mockRepository struct {
-	authors []*author.Author
-	err     error
+// newTestRepository returns an in-memory repository seeded with authors.
+func newTestRepository(authors []*author.Author) *memory.AuthorRepository {
+	repo := memory.NewAuthorRepository()
+	repo.SeedAuthors(authors...)
+	return repo
 }
 
-func (m *mockRepository) GetAuthor(ctx context.Context, id int64) (*author.Author, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	for _, a := range m.authors {
-		if a.ID == id {
-			return a, nil
-		}
-	}
-	return nil, nil
-}
+// testTx runs use case transactions directly against the in-memory
+// repositories.
+var testTx = memory.NewTxManager()
 
-func (m *mockRepository) ListAuthors(ctx context.Context) ([]*author.Author, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	return m.authors, nil
+// eventLog records the events use cases emit.
+type eventLog struct {
+	events []author.Event
 }
 
-func (m *mockRepository) CreateAuthor(ctx context.Context, params author.CreateAuthorParams) (*author.Author, error) {
-	if m.err != nil {
-		return nil, m.err
-	}
-	a := &author.Author{
-		ID:   1,
-		Name: params.Name,
-		Bio:  params.Bio,
-	}
-	m.authors = append(m.authors, a)
-	return a, nil
+func (l *eventLog) RecordEvent(ctx context.Context, e author.Event) error {
+	l.events = append(l.events, e)
+	return nil
 }
 
-func (m *mockRepository) UpdateAuthor(ctx context.Context, params author.UpdateAuthorParams) error {
-	if m.err != nil {
-		return m.err
-	}
-	for i, a := range m.authors {
-		if a.ID == params.ID {
-			m.authors[i].Name = params.Name
-			m.authors[i].Bio = params.Bio
-			return nil
-		}
-	}
-	return nil
+// failingRepository fails every streaming call with err. Other methods are
+// not implemented and panic if called.
+type failingRepository struct {
+	author.Repository
+	err error
 }
 
-func (m *mockRepository) DeleteAuthor(ctx context.Context, id int64) error {
-	if m.err != nil {
-		return m.err
-	}
-	for i, a := range m.authors {
-		if a.ID == id {
-			m.authors = append(m.authors[:i], m.authors[i+1:]...)
-			return nil
-		}
-	}
-	return nil
+func (f *failingRepository) StreamAuthors(ctx context.Context, fn func(*author.Author) error) error {
+	return f.err
 }
 
 func TestListAuthorsUseCase_Execute(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{
-			{
-				ID:   1,
-				Name: "Alice",
-				Bio:  pgtype.Text{String: "Author 1", Valid: true},
-			},
-			{
-				ID:   2,
-				Name: "Bob",
-				Bio:  pgtype.Text{String: "Author 2", Valid: true},
-			},
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Author 1", Valid: true},
 		},
-	}
-	uc := NewListAuthorsUseCase(mockRepo)
+		{
+			ID:   2,
+			Name: "Bob",
+			Bio:  pgtype.Text{String: "Author 2", Valid: true},
+		},
+	})
+	uc := NewListAuthorsUseCase(repo)
 
 	// Act
-	authors, err := uc.Execute(context.Background())
+	page, err := uc.Execute(context.Background(), ListAuthorsParams{})
 
 	// Assert
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(authors) != 2 {
-		t.Errorf("expected 2 authors, got %d", len(authors))
+	if len(page.Authors) != 2 {
+		t.Errorf("expected 2 authors, got %d", len(page.Authors))
+	}
+	if page.Authors[0].Name != "Alice" {
+		t.Errorf("expected name 'Alice', got '%s'", page.Authors[0].Name)
 	}
-	if authors[0].Name != "Alice" {
-		t.Errorf("expected name 'Alice', got '%s'", authors[0].Name)
+	if page.NextCursor != "" {
+		t.Errorf("expected no next cursor, got '%s'", page.NextCursor)
 	}
 }
 
 func TestListAuthorsUseCase_ExecuteEmpty(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{},
-	}
-	uc := NewListAuthorsUseCase(mockRepo)
+	repo := newTestRepository(nil)
+	uc := NewListAuthorsUseCase(repo)
 
 	// Act
-	authors, err := uc.Execute(context.Background())
+	page, err := uc.Execute(context.Background(), ListAuthorsParams{})
 
 	// Assert
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(authors) != 0 {
-		t.Errorf("expected 0 authors, got %d", len(authors))
+	if len(page.Authors) != 0 {
+		t.Errorf("expected 0 authors, got %d", len(page.Authors))
+	}
+}
+
+func TestListAuthorsUseCase_ExecutePaginates(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{ID: 3, Name: "Carol"},
+		{ID: 1, Name: "Alice"},
+		{ID: 4, Name: "Bob"},
+		{ID: 2, Name: "Bob"},
+	})
+	uc := NewListAuthorsUseCase(repo)
+
+	// Act
+	first, err := uc.Execute(context.Background(), ListAuthorsParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := uc.Execute(context.Background(), ListAuthorsParams{Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	if len(first.Authors) != 2 || first.Authors[0].ID != 1 || first.Authors[1].ID != 2 {
+		t.Errorf("unexpected first page: %+v", first.Authors)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("expected next cursor on first page")
+	}
+	if len(second.Authors) != 2 || second.Authors[0].ID != 4 || second.Authors[1].ID != 3 {
+		t.Errorf("unexpected second page: %+v", second.Authors)
+	}
+	if second.NextCursor != "" {
+		t.Errorf("expected no next cursor on last page, got '%s'", second.NextCursor)
+	}
+}
+
+func TestListAuthorsUseCase_ExecuteInvalidParams(t *testing.T) {
+	// Arrange
+	uc := NewListAuthorsUseCase(newTestRepository(nil))
+
+	// Act
+	_, cursorErr := uc.Execute(context.Background(), ListAuthorsParams{Cursor: "not-a-cursor"})
+	_, limitErr := uc.Execute(context.Background(), ListAuthorsParams{Limit: MaxPageSize + 1})
+
+	// Assert
+	var domainErr *apperrors.DomainError
+	if !errors.As(cursorErr, &domainErr) || domainErr.Code != apperrors.CodeValidation {
+		t.Errorf("expected validation error for cursor, got %v", cursorErr)
+	}
+	if !errors.As(limitErr, &domainErr) || domainErr.Code != apperrors.CodeValidation {
+		t.Errorf("expected validation error for limit, got %v", limitErr)
 	}
 }
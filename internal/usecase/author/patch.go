@@ -0,0 +1,74 @@
+package author
+
+import (
+	"context"
+	"strings"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// PatchAuthorUseCase partially updates an existing author.
+type PatchAuthorUseCase struct {
+	repo   author.Repository
+	tx     transaction.Manager
+	events author.EventRecorder
+}
+
+// NewPatchAuthorUseCase creates a new PatchAuthorUseCase.
+func NewPatchAuthorUseCase(repo author.Repository, tx transaction.Manager, events author.EventRecorder) *PatchAuthorUseCase {
+	return &PatchAuthorUseCase{repo: repo, tx: tx, events: events}
+}
+
+// Execute applies params to an author and records an author.updated event
+// carrying its new state in the same transaction. It returns the patched
+// author. A patch that sets no field changes nothing and records no event,
+// but still fails if params.Version is stale.
+func (u *PatchAuthorUseCase) Execute(ctx context.Context, params author.PatchAuthorParams) (*author.Author, error) {
+	if err := validatePatchAuthor(params); err != nil {
+		return nil, err
+	}
+
+	if !params.Name.Set && !params.Bio.Set {
+		a, err := u.repo.GetAuthor(ctx, params.ID)
+		if err != nil {
+			return nil, err
+		}
+		if params.Version != 0 && a.Version != params.Version {
+			return nil, apperrors.PreconditionFailedError
+		}
+		return a, nil
+	}
+
+	var patched *author.Author
+	err := u.tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := u.repo.PatchAuthor(ctx, params); err != nil {
+			return err
+		}
+		a, err := u.repo.GetAuthor(ctx, params.ID)
+		if err != nil {
+			return err
+		}
+		patched = a
+		return u.events.RecordEvent(ctx, author.NewEvent(author.EventUpdated, a.ID, a))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// validatePatchAuthor reports the first problem with params, if any. The
+// name may be left out but not removed.
+func validatePatchAuthor(params author.PatchAuthorParams) error {
+	switch {
+	case params.Name.Null:
+		return apperrors.ValidationError("name cannot be null")
+	case params.Name.Set && strings.TrimSpace(params.Name.Value) == "":
+		return apperrors.ValidationError("name is required")
+	case strings.ContainsRune(params.Name.Value, 0) || strings.ContainsRune(params.Bio.Value, 0):
+		return apperrors.ValidationError("text must not contain NUL bytes")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestPatchAuthorUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Original", Valid: true},
+		},
+	})
+	events := &eventLog{}
+	uc := NewPatchAuthorUseCase(repo, testTx, events)
+
+	// Act
+	patched, err := uc.Execute(context.Background(), author.PatchAuthorParams{
+		ID:      1,
+		Name:    author.PatchValue("Alicia"),
+		Version: 1,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patched.Name != "Alicia" || patched.Bio.String != "Original" || patched.Version != 2 {
+		t.Errorf("expected only the name to change, got %+v", patched)
+	}
+	if len(events.events) != 1 || events.events[0].Type != author.EventUpdated || events.events[0].Author.Version != 2 {
+		t.Errorf("expected one updated event, got %+v", events.events)
+	}
+}
+
+func TestPatchAuthorUseCase_ExecuteEmptyPatch(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{{ID: 1, Name: "Alice", Version: 3}})
+	events := &eventLog{}
+	uc := NewPatchAuthorUseCase(repo, testTx, events)
+
+	// Act
+	current, err := uc.Execute(context.Background(), author.PatchAuthorParams{ID: 1, Version: 3})
+	_, staleErr := uc.Execute(context.Background(), author.PatchAuthorParams{ID: 1, Version: 2})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Version != 3 || len(events.events) != 0 {
+		t.Errorf("expected no change and no event, got %+v and %d event(s)", current, len(events.events))
+	}
+	if !errors.Is(staleErr, apperrors.PreconditionFailedError) {
+		t.Errorf("expected precondition failed for a stale version, got %v", staleErr)
+	}
+}
+
+func TestPatchAuthorUseCase_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		params author.PatchAuthorParams
+	}{
+		{"null name", author.PatchAuthorParams{ID: 1, Name: author.PatchNull[string]()}},
+		{"blank name", author.PatchAuthorParams{ID: 1, Name: author.PatchValue(" ")}},
+		{"NUL in bio", author.PatchAuthorParams{ID: 1, Bio: author.PatchValue("a\x00b")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := newTestRepository([]*author.Author{{ID: 1, Name: "Alice"}})
+			uc := NewPatchAuthorUseCase(repo, testTx, &eventLog{})
+
+			// Act
+			_, err := uc.Execute(context.Background(), tt.params)
+
+			// Assert
+			if !errors.Is(err, apperrors.ValidationError("")) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+			if a, _ := repo.GetAuthor(context.Background(), 1); a.Version != 1 {
+				t.Errorf("expected the author to be unchanged, got %+v", a)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package author
+
+import (
+	"context"
+	"time"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// PurgeDeletedAuthorsUseCase permanently removes authors that have been
+// soft-deleted for longer than a retention period.
+type PurgeDeletedAuthorsUseCase struct {
+	repo author.Repository
+	now  func() time.Time
+}
+
+// NewPurgeDeletedAuthorsUseCase creates a new PurgeDeletedAuthorsUseCase.
+func NewPurgeDeletedAuthorsUseCase(repo author.Repository) *PurgeDeletedAuthorsUseCase {
+	return &PurgeDeletedAuthorsUseCase{repo: repo, now: time.Now}
+}
+
+// Execute purges authors soft-deleted more than retention ago and returns
+// the number of authors removed.
+func (u *PurgeDeletedAuthorsUseCase) Execute(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		return 0, apperrors.ValidationError("retention must be positive")
+	}
+	return u.repo.PurgeDeletedAuthors(ctx, u.now().Add(-retention))
+}
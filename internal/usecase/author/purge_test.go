@@ -0,0 +1,61 @@
+package author
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+)
+
+func TestPurgeDeletedAuthorsUseCase_Execute(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Active",
+		},
+		{
+			ID:        2,
+			Name:      "Recently deleted",
+			DeletedAt: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+		},
+		{
+			ID:        3,
+			Name:      "Long deleted",
+			DeletedAt: pgtype.Timestamptz{Time: now.Add(-48 * time.Hour), Valid: true},
+		},
+	})
+	uc := NewPurgeDeletedAuthorsUseCase(repo)
+	uc.now = func() time.Time { return now }
+
+	// Act
+	n, err := uc.Execute(context.Background(), 24*time.Hour)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 purged author, got %d", n)
+	}
+	remaining, _ := repo.ListAuthorsPage(context.Background(), author.ListAuthorsPageParams{Limit: 10, IncludeDeleted: true})
+	if len(remaining) != 2 {
+		t.Errorf("expected 2 remaining authors, got %d", len(remaining))
+	}
+}
+
+func TestPurgeDeletedAuthorsUseCase_ExecuteInvalidRetention(t *testing.T) {
+	// Arrange
+	uc := NewPurgeDeletedAuthorsUseCase(newTestRepository(nil))
+
+	// Act
+	_, err := uc.Execute(context.Background(), 0)
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+}
@@ -0,0 +1,35 @@
+package author
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+)
+
+// RestoreAuthorUseCase restores a soft-deleted author.
+type RestoreAuthorUseCase struct {
+	repo   author.Repository
+	tx     transaction.Manager
+	events author.EventRecorder
+}
+
+// NewRestoreAuthorUseCase creates a new RestoreAuthorUseCase.
+func NewRestoreAuthorUseCase(repo author.Repository, tx transaction.Manager, events author.EventRecorder) *RestoreAuthorUseCase {
+	return &RestoreAuthorUseCase{repo: repo, tx: tx, events: events}
+}
+
+// Execute restores a soft-deleted author by ID and records an
+// author.restored event in the same transaction.
+func (u *RestoreAuthorUseCase) Execute(ctx context.Context, id int64) error {
+	return u.tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := u.repo.RestoreAuthor(ctx, id); err != nil {
+			return err
+		}
+		restored, err := u.repo.GetAuthor(ctx, id)
+		if err != nil {
+			return err
+		}
+		return u.events.RecordEvent(ctx, author.NewEvent(author.EventRestored, id, restored))
+	})
+}
@@ -0,0 +1,54 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestRestoreAuthorUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:        1,
+			Name:      "Alice",
+			DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		},
+	})
+	uc := NewRestoreAuthorUseCase(repo, testTx, &eventLog{})
+
+	// Act
+	err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetAuthor(context.Background(), 1); err != nil {
+		t.Errorf("expected author to be visible again, got %v", err)
+	}
+}
+
+func TestRestoreAuthorUseCase_ExecuteNotFound(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+		},
+	})
+	uc := NewRestoreAuthorUseCase(repo, testTx, &eventLog{})
+
+	// Act
+	err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package author
+
+import (
+	"context"
+	"strings"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// SearchAuthorsUseCase finds authors by bio text, name prefix and bio presence.
+type SearchAuthorsUseCase struct {
+	repo author.Repository
+}
+
+// NewSearchAuthorsUseCase creates a new SearchAuthorsUseCase.
+func NewSearchAuthorsUseCase(repo author.Repository) *SearchAuthorsUseCase {
+	return &SearchAuthorsUseCase{repo: repo}
+}
+
+// Execute searches authors. A zero limit uses DefaultPageSize.
+func (u *SearchAuthorsUseCase) Execute(ctx context.Context, params author.SearchAuthorsParams) ([]*author.Author, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	switch {
+	case params.Limit == 0:
+		params.Limit = DefaultPageSize
+	case params.Limit < 0 || params.Limit > MaxPageSize:
+		return nil, apperrors.ValidationError("limit must be between 1 and 200")
+	}
+	return u.repo.SearchAuthors(ctx, params)
+}
@@ -0,0 +1,84 @@
+package author
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+)
+
+func TestSearchAuthorsUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Writes about gardening", Valid: true},
+		},
+		{
+			ID:   2,
+			Name: "Bob",
+			Bio:  pgtype.Text{String: "Writes about sailing", Valid: true},
+		},
+		{
+			ID:   3,
+			Name: "Alan",
+		},
+	})
+	uc := NewSearchAuthorsUseCase(repo)
+
+	// Act
+	authors, err := uc.Execute(context.Background(), author.SearchAuthorsParams{Query: "  sailing "})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(authors) != 1 || authors[0].ID != 2 {
+		t.Errorf("expected only author 2, got %+v", authors)
+	}
+}
+
+func TestSearchAuthorsUseCase_ExecuteHasBioFilter(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Author 1", Valid: true},
+		},
+		{
+			ID:   3,
+			Name: "Alan",
+		},
+	})
+	uc := NewSearchAuthorsUseCase(repo)
+
+	// Act
+	authors, err := uc.Execute(context.Background(), author.SearchAuthorsParams{
+		NamePrefix: "Al",
+		HasBio:     pgtype.Bool{Bool: false, Valid: true},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(authors) != 1 || authors[0].Name != "Alan" {
+		t.Errorf("expected only Alan, got %+v", authors)
+	}
+}
+
+func TestSearchAuthorsUseCase_ExecuteInvalidLimit(t *testing.T) {
+	// Arrange
+	uc := NewSearchAuthorsUseCase(newTestRepository(nil))
+
+	// Act
+	_, err := uc.Execute(context.Background(), author.SearchAuthorsParams{Limit: -1})
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+}
@@ -4,19 +4,32 @@ import (
 	"context"
 
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
 )
 
 // UpdateAuthorUseCase updates an existing author.
 type UpdateAuthorUseCase struct {
-	repo author.Repository
+	repo   author.Repository
+	tx     transaction.Manager
+	events author.EventRecorder
 }
 
 // NewUpdateAuthorUseCase creates a new UpdateAuthorUseCase.
-func NewUpdateAuthorUseCase(repo author.Repository) *UpdateAuthorUseCase {
-	return &UpdateAuthorUseCase{repo: repo}
+func NewUpdateAuthorUseCase(repo author.Repository, tx transaction.Manager, events author.EventRecorder) *UpdateAuthorUseCase {
+	return &UpdateAuthorUseCase{repo: repo, tx: tx, events: events}
 }
 
-// Execute updates an author.
+// Execute updates an author and records an author.updated event carrying
+// its new state in the same transaction.
 func (u *UpdateAuthorUseCase) Execute(ctx context.Context, params author.UpdateAuthorParams) error {
-	return u.repo.UpdateAuthor(ctx, params)
+	return u.tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdateAuthor(ctx, params); err != nil {
+			return err
+		}
+		updated, err := u.repo.GetAuthor(ctx, params.ID)
+		if err != nil {
+			return err
+		}
+		return u.events.RecordEvent(ctx, author.NewEvent(author.EventUpdated, updated.ID, updated))
+	})
 }
@@ -2,24 +2,24 @@ package author
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
 )
 
 func TestUpdateAuthorUseCase_Execute(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{
-			{
-				ID:   1,
-				Name: "Alice",
-				Bio:  pgtype.Text{String: "Original", Valid: true},
-			},
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:   1,
+			Name: "Alice",
+			Bio:  pgtype.Text{String: "Original", Valid: true},
 		},
-	}
-	uc := NewUpdateAuthorUseCase(mockRepo)
+	})
+	uc := NewUpdateAuthorUseCase(repo, testTx, &eventLog{})
 	params := author.UpdateAuthorParams{
 		ID:   1,
 		Name: "Alice Updated",
@@ -33,20 +33,19 @@ func TestUpdateAuthorUseCase_Execute(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if mockRepo.authors[0].Name != "Alice Updated" {
-		t.Errorf("expected name 'Alice Updated', got '%s'", mockRepo.authors[0].Name)
+	updated, _ := repo.GetAuthor(context.Background(), 1)
+	if updated.Name != "Alice Updated" {
+		t.Errorf("expected name 'Alice Updated', got '%s'", updated.Name)
 	}
-	if mockRepo.authors[0].Bio.String != "Updated bio" {
-		t.Errorf("expected bio 'Updated bio', got '%s'", mockRepo.authors[0].Bio.String)
+	if updated.Bio.String != "Updated bio" {
+		t.Errorf("expected bio 'Updated bio', got '%s'", updated.Bio.String)
 	}
 }
 
 func TestUpdateAuthorUseCase_ExecuteNotFound(t *testing.T) {
 	// Arrange
-	mockRepo := &mockRepository{
-		authors: []*author.Author{},
-	}
-	uc := NewUpdateAuthorUseCase(mockRepo)
+	repo := newTestRepository(nil)
+	uc := NewUpdateAuthorUseCase(repo, testTx, &eventLog{})
 	params := author.UpdateAuthorParams{
 		ID:   999,
 		Name: "Ghost",
@@ -56,12 +55,76 @@ func TestUpdateAuthorUseCase_ExecuteNotFound(t *testing.T) {
 	// Act
 	err := uc.Execute(context.Background(), params)
 
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestUpdateAuthorUseCase_ExecuteVersionMismatch(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{
+		{
+			ID:      1,
+			Name:    "Alice",
+			Version: 2,
+		},
+	})
+	uc := NewUpdateAuthorUseCase(repo, testTx, &eventLog{})
+	params := author.UpdateAuthorParams{
+		ID:      1,
+		Name:    "Alice Updated",
+		Version: 1,
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), params)
+
+	// Assert
+	if !errors.Is(err, apperrors.PreconditionFailedError) {
+		t.Fatalf("expected precondition failed error, got %v", err)
+	}
+	if unchanged, _ := repo.GetAuthor(context.Background(), 1); unchanged.Name != "Alice" {
+		t.Errorf("expected name to stay 'Alice', got '%s'", unchanged.Name)
+	}
+}
+
+func TestUpdateAuthorUseCase_ExecuteRecordsEvent(t *testing.T) {
+	// Arrange
+	repo := newTestRepository([]*author.Author{{ID: 1, Name: "Alice"}})
+	events := &eventLog{}
+	uc := NewUpdateAuthorUseCase(repo, testTx, events)
+
+	// Act
+	err := uc.Execute(context.Background(), author.UpdateAuthorParams{ID: 1, Name: "Alicia", Version: 1})
+
 	// Assert
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	// Mock repo silently ignores non-existent authors
-	if len(mockRepo.authors) != 0 {
-		t.Errorf("expected no authors, got %d", len(mockRepo.authors))
+	if len(events.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events.events))
+	}
+	e := events.events[0]
+	if e.Type != author.EventUpdated || e.Author.Name != "Alicia" || e.Author.Version != 2 {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
+
+func TestUpdateAuthorUseCase_ExecuteFailureRecordsNoEvent(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	events := &eventLog{}
+	uc := NewUpdateAuthorUseCase(repo, testTx, events)
+
+	// Act
+	err := uc.Execute(context.Background(), author.UpdateAuthorParams{ID: 1, Name: "Ghost"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(events.events) != 0 {
+		t.Errorf("expected no events, got %d", len(events.events))
 	}
 }
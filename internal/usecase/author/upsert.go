@@ -0,0 +1,73 @@
+package author
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/transaction"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+// maxExternalIDLen is the longest external ID, in characters, the schema
+// accepts.
+const maxExternalIDLen = 255
+
+// UpsertAuthorUseCase creates or updates an author by its external ID.
+type UpsertAuthorUseCase struct {
+	repo   author.Repository
+	tx     transaction.Manager
+	events author.EventRecorder
+}
+
+// NewUpsertAuthorUseCase creates a new UpsertAuthorUseCase.
+func NewUpsertAuthorUseCase(repo author.Repository, tx transaction.Manager, events author.EventRecorder) *UpsertAuthorUseCase {
+	return &UpsertAuthorUseCase{repo: repo, tx: tx, events: events}
+}
+
+// Execute creates the author with params.ExternalID, or updates it if it
+// exists, and records an author.created or author.updated event in the same
+// transaction. It reports whether the author was created.
+func (u *UpsertAuthorUseCase) Execute(ctx context.Context, params author.UpsertAuthorParams) (*author.Author, bool, error) {
+	if err := validateUpsertAuthor(params); err != nil {
+		return nil, false, err
+	}
+
+	var (
+		upserted *author.Author
+		created  bool
+	)
+	err := u.tx.RunInTx(ctx, func(ctx context.Context) error {
+		a, isNew, err := u.repo.UpsertAuthor(ctx, params)
+		if err != nil {
+			return err
+		}
+		upserted, created = a, isNew
+		eventType := author.EventUpdated
+		if isNew {
+			eventType = author.EventCreated
+		}
+		return u.events.RecordEvent(ctx, author.NewEvent(eventType, a.ID, a))
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return upserted, created, nil
+}
+
+// validateUpsertAuthor reports the first problem with params, if any.
+func validateUpsertAuthor(params author.UpsertAuthorParams) error {
+	switch {
+	case params.ExternalID == "":
+		return apperrors.ValidationError("external id is required")
+	case utf8.RuneCountInString(params.ExternalID) > maxExternalIDLen:
+		return apperrors.ValidationError("external id is too long")
+	case strings.ContainsRune(params.ExternalID, 0):
+		return apperrors.ValidationError("external id must not contain NUL bytes")
+	}
+	if err := validateCreateAuthor(author.CreateAuthorParams{Name: params.Name, Bio: params.Bio}); err != nil {
+		return apperrors.ValidationError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestUpsertAuthorUseCase_CreatesThenUpdates(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(nil)
+	events := &eventLog{}
+	uc := NewUpsertAuthorUseCase(repo, testTx, events)
+	ctx := context.Background()
+
+	// Act
+	created, isNew, err := uc.Execute(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "Charlie"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated, updatedIsNew, err := uc.Execute(ctx, author.UpsertAuthorParams{ExternalID: "ext-1", Name: "Charles"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isNew || updatedIsNew {
+		t.Errorf("expected created then updated, got %t and %t", isNew, updatedIsNew)
+	}
+	if updated.ID != created.ID || updated.Name != "Charles" {
+		t.Errorf("expected author %d renamed, got %+v", created.ID, updated)
+	}
+	if len(events.events) != 2 || events.events[0].Type != author.EventCreated || events.events[1].Type != author.EventUpdated {
+		t.Errorf("expected created and updated events, got %+v", events.events)
+	}
+}
+
+func TestUpsertAuthorUseCase_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		params author.UpsertAuthorParams
+	}{
+		{"missing external id", author.UpsertAuthorParams{Name: "Charlie"}},
+		{"long external id", author.UpsertAuthorParams{ExternalID: strings.Repeat("x", 256), Name: "Charlie"}},
+		{"missing name", author.UpsertAuthorParams{ExternalID: "ext-1", Name: " "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			events := &eventLog{}
+			uc := NewUpsertAuthorUseCase(newTestRepository(nil), testTx, events)
+
+			// Act
+			_, _, err := uc.Execute(context.Background(), tt.params)
+
+			// Assert
+			if !errors.Is(err, apperrors.ValidationError("")) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+			if len(events.events) != 0 {
+				t.Errorf("expected no events, got %d", len(events.events))
+			}
+		})
+	}
+}
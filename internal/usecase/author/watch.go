@@ -0,0 +1,24 @@
+package author
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+)
+
+// WatchAuthorsUseCase follows live author changes.
+type WatchAuthorsUseCase struct {
+	feed author.ChangeFeed
+}
+
+// NewWatchAuthorsUseCase creates a new WatchAuthorsUseCase.
+func NewWatchAuthorsUseCase(feed author.ChangeFeed) *WatchAuthorsUseCase {
+	return &WatchAuthorsUseCase{feed: feed}
+}
+
+// Execute subscribes to the changes after lastID until ctx is done. gap
+// reports that changes were missed since lastID, so the caller should
+// reload the authors it shows.
+func (u *WatchAuthorsUseCase) Execute(ctx context.Context, lastID string) (changes <-chan author.Change, gap bool) {
+	return u.feed.Subscribe(ctx, lastID)
+}
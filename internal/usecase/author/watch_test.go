@@ -0,0 +1,44 @@
+package author
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+)
+
+// fakeFeed replays changes to every subscriber and records the last ID.
+type fakeFeed struct {
+	changes []author.Change
+	lastID  string
+}
+
+func (f *fakeFeed) Subscribe(ctx context.Context, lastID string) (<-chan author.Change, bool) {
+	f.lastID = lastID
+	ch := make(chan author.Change, len(f.changes))
+	for _, c := range f.changes {
+		ch <- c
+	}
+	close(ch)
+	return ch, lastID != ""
+}
+
+func TestWatchAuthorsUseCase_Execute(t *testing.T) {
+	// Arrange
+	feed := &fakeFeed{changes: []author.Change{{ID: "e-2", Type: author.EventDeleted, AuthorID: 3}}}
+	uc := NewWatchAuthorsUseCase(feed)
+
+	// Act
+	ch, gap := uc.Execute(context.Background(), "e-1")
+
+	// Assert
+	if feed.lastID != "e-1" {
+		t.Errorf("expected last ID to be passed on, got %q", feed.lastID)
+	}
+	if !gap {
+		t.Error("expected the feed's gap to be reported")
+	}
+	if c := <-ch; c.ID != "e-2" || c.AuthorID != 3 {
+		t.Errorf("unexpected change %+v", c)
+	}
+}
@@ -0,0 +1,29 @@
+package book
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+)
+
+// CreateBookUseCase creates a new book.
+type CreateBookUseCase struct {
+	repo    book.Repository
+	authors author.Repository
+}
+
+// NewCreateBookUseCase creates a new CreateBookUseCase.
+func NewCreateBookUseCase(repo book.Repository, authors author.Repository) *CreateBookUseCase {
+	return &CreateBookUseCase{repo: repo, authors: authors}
+}
+
+// Execute creates a new book. It returns errors.NotFoundError when the author
+// does not exist or is soft-deleted; the schema alone only rejects missing
+// authors.
+func (u *CreateBookUseCase) Execute(ctx context.Context, params book.CreateBookParams) (*book.Book, error) {
+	if _, err := u.authors.GetAuthor(ctx, params.AuthorID); err != nil {
+		return nil, err
+	}
+	return u.repo.CreateBook(ctx, params)
+}
@@ -0,0 +1,59 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestCreateBookUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo, authors := newTestRepositories()
+	uc := NewCreateBookUseCase(repo, authors)
+	params := book.CreateBookParams{
+		AuthorID:    1,
+		Title:       "New Book",
+		ISBN:        pgtype.Text{String: "978-0000000002", Valid: true},
+		PublishedAt: pgtype.Date{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+
+	// Act
+	created, err := uc.Execute(context.Background(), params)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID == 0 {
+		t.Errorf("expected non-zero ID, got %d", created.ID)
+	}
+	if created.Title != "New Book" || created.AuthorID != 1 {
+		t.Errorf("unexpected book: %+v", created)
+	}
+}
+
+func TestCreateBookUseCase_ExecuteMissingAuthor(t *testing.T) {
+	for name, authorID := range map[string]int64{"missing": 4, "soft-deleted": 3} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo, authors := newTestRepositories()
+			uc := NewCreateBookUseCase(repo, authors)
+
+			// Act
+			_, err := uc.Execute(context.Background(), book.CreateBookParams{AuthorID: authorID, Title: "Orphan"})
+
+			// Assert
+			if !errors.Is(err, apperrors.NotFoundError) {
+				t.Errorf("expected not found, got %v", err)
+			}
+			if stored, _ := repo.ListBooks(context.Background()); len(stored) != 0 {
+				t.Errorf("expected no book stored, got %+v", stored)
+			}
+		})
+	}
+}
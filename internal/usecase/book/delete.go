@@ -0,0 +1,22 @@
+package book
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+)
+
+// DeleteBookUseCase deletes a book.
+type DeleteBookUseCase struct {
+	repo book.Repository
+}
+
+// NewDeleteBookUseCase creates a new DeleteBookUseCase.
+func NewDeleteBookUseCase(repo book.Repository) *DeleteBookUseCase {
+	return &DeleteBookUseCase{repo: repo}
+}
+
+// Execute deletes a book by ID.
+func (u *DeleteBookUseCase) Execute(ctx context.Context, id int64) error {
+	return u.repo.DeleteBook(ctx, id)
+}
@@ -0,0 +1,45 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestDeleteBookUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo, _ := newTestRepositories(
+		&book.Book{ID: 1, AuthorID: 1, Title: "First Book"},
+		&book.Book{ID: 2, AuthorID: 1, Title: "Second Book"},
+	)
+	uc := NewDeleteBookUseCase(repo)
+
+	// Act
+	err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	remaining, _ := repo.ListBooks(context.Background())
+	if len(remaining) != 1 || remaining[0].ID != 2 {
+		t.Errorf("expected only book 2 to remain, got %+v", remaining)
+	}
+}
+
+func TestDeleteBookUseCase_ExecuteNotFound(t *testing.T) {
+	// Arrange
+	repo, _ := newTestRepositories()
+	uc := NewDeleteBookUseCase(repo)
+
+	// Act
+	err := uc.Execute(context.Background(), 999)
+
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package book
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+)
+
+// GetBookUseCase retrieves a single book by ID.
+type GetBookUseCase struct {
+	repo book.Repository
+}
+
+// NewGetBookUseCase creates a new GetBookUseCase.
+func NewGetBookUseCase(repo book.Repository) *GetBookUseCase {
+	return &GetBookUseCase{repo: repo}
+}
+
+// Execute retrieves a single book by ID.
+func (u *GetBookUseCase) Execute(ctx context.Context, id int64) (*book.Book, error) {
+	return u.repo.GetBook(ctx, id)
+}
@@ -0,0 +1,41 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestGetBookUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo, _ := newTestRepositories(&book.Book{ID: 1, AuthorID: 1, Title: "First Book"})
+	uc := NewGetBookUseCase(repo)
+
+	// Act
+	b, err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Title != "First Book" {
+		t.Errorf("expected title 'First Book', got '%s'", b.Title)
+	}
+}
+
+func TestGetBookUseCase_ExecuteNotFound(t *testing.T) {
+	// Arrange
+	repo, _ := newTestRepositories()
+	uc := NewGetBookUseCase(repo)
+
+	// Act
+	_, err := uc.Execute(context.Background(), 999)
+
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package book
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+)
+
+// ListBooksUseCase retrieves all books.
+type ListBooksUseCase struct {
+	repo book.Repository
+}
+
+// NewListBooksUseCase creates a new ListBooksUseCase.
+func NewListBooksUseCase(repo book.Repository) *ListBooksUseCase {
+	return &ListBooksUseCase{repo: repo}
+}
+
+// Execute retrieves all books.
+func (u *ListBooksUseCase) Execute(ctx context.Context) ([]*book.Book, error) {
+	return u.repo.ListBooks(ctx)
+}
@@ -0,0 +1,28 @@
+package book
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+)
+
+// ListAuthorBooksUseCase retrieves the books written by an author.
+type ListAuthorBooksUseCase struct {
+	repo    book.Repository
+	authors author.Repository
+}
+
+// NewListAuthorBooksUseCase creates a new ListAuthorBooksUseCase.
+func NewListAuthorBooksUseCase(repo book.Repository, authors author.Repository) *ListAuthorBooksUseCase {
+	return &ListAuthorBooksUseCase{repo: repo, authors: authors}
+}
+
+// Execute retrieves the books written by the author with authorID. It returns
+// errors.NotFoundError when the author does not exist or is soft-deleted.
+func (u *ListAuthorBooksUseCase) Execute(ctx context.Context, authorID int64) ([]*book.Book, error) {
+	if _, err := u.authors.GetAuthor(ctx, authorID); err != nil {
+		return nil, err
+	}
+	return u.repo.ListBooksByAuthor(ctx, authorID)
+}
@@ -0,0 +1,54 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestListAuthorBooksUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo, authors := newTestRepositories(
+		&book.Book{ID: 1, AuthorID: 1, Title: "Alice's Book"},
+		&book.Book{ID: 2, AuthorID: 2, Title: "Bob's Book"},
+		&book.Book{ID: 3, AuthorID: 1, Title: "Alice's Sequel"},
+	)
+	uc := NewListAuthorBooksUseCase(repo, authors)
+
+	// Act
+	books, err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(books) != 2 {
+		t.Fatalf("expected 2 books, got %d", len(books))
+	}
+	for _, b := range books {
+		if b.AuthorID != 1 {
+			t.Errorf("expected only books by author 1, got author %d", b.AuthorID)
+		}
+	}
+}
+
+func TestListAuthorBooksUseCase_ExecuteMissingAuthor(t *testing.T) {
+	for name, authorID := range map[string]int64{"missing": 4, "soft-deleted": 3} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo, authors := newTestRepositories()
+			uc := NewListAuthorBooksUseCase(repo, authors)
+
+			// Act
+			_, err := uc.Execute(context.Background(), authorID)
+
+			// Assert
+			if !errors.Is(err, apperrors.NotFoundError) {
+				t.Errorf("expected not found, got %v", err)
+			}
+		})
+	}
+}
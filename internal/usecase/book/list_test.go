@@ -0,0 +1,75 @@
+package book
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	"github.com/seldomhappy/sqlc-test/internal/infrastructure/memory"
+)
+
+// newTestRepositories returns in-memory book and author repositories sharing
+// one store, seeded with books and with live authors 1 and 2 and soft-deleted
+// author 3.
+func newTestRepositories(books ...*book.Book) (*memory.BookRepository, *memory.AuthorRepository) {
+	store := memory.NewStore()
+	store.Authors().SeedAuthors(
+		&author.Author{ID: 1, Name: "Alice"},
+		&author.Author{ID: 2, Name: "Bob"},
+		&author.Author{ID: 3, Name: "Carol", DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+	)
+	store.Books().SeedBooks(books...)
+	return store.Books(), store.Authors()
+}
+
+func TestListBooksUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo, _ := newTestRepositories(
+		&book.Book{
+			ID:       1,
+			AuthorID: 1,
+			Title:    "Second Book",
+			ISBN:     pgtype.Text{String: "978-0000000001", Valid: true},
+		},
+		&book.Book{
+			ID:       2,
+			AuthorID: 2,
+			Title:    "First Book",
+		},
+	)
+	uc := NewListBooksUseCase(repo)
+
+	// Act
+	books, err := uc.Execute(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(books) != 2 {
+		t.Fatalf("expected 2 books, got %d", len(books))
+	}
+	if books[0].Title != "First Book" {
+		t.Errorf("expected books ordered by title, got '%s' first", books[0].Title)
+	}
+}
+
+func TestListBooksUseCase_ExecuteEmpty(t *testing.T) {
+	// Arrange
+	repo, _ := newTestRepositories()
+	uc := NewListBooksUseCase(repo)
+
+	// Act
+	books, err := uc.Execute(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(books) != 0 {
+		t.Errorf("expected 0 books, got %d", len(books))
+	}
+}
@@ -0,0 +1,28 @@
+package book
+
+import (
+	"context"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/author"
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+)
+
+// UpdateBookUseCase updates an existing book.
+type UpdateBookUseCase struct {
+	repo    book.Repository
+	authors author.Repository
+}
+
+// NewUpdateBookUseCase creates a new UpdateBookUseCase.
+func NewUpdateBookUseCase(repo book.Repository, authors author.Repository) *UpdateBookUseCase {
+	return &UpdateBookUseCase{repo: repo, authors: authors}
+}
+
+// Execute updates a book. Like CreateBookUseCase it returns
+// errors.NotFoundError when the author does not exist or is soft-deleted.
+func (u *UpdateBookUseCase) Execute(ctx context.Context, params book.UpdateBookParams) error {
+	if _, err := u.authors.GetAuthor(ctx, params.AuthorID); err != nil {
+		return err
+	}
+	return u.repo.UpdateBook(ctx, params)
+}
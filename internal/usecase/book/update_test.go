@@ -0,0 +1,62 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seldomhappy/sqlc-test/internal/domain/book"
+	apperrors "github.com/seldomhappy/sqlc-test/pkg/errors"
+)
+
+func TestUpdateBookUseCase_Execute(t *testing.T) {
+	// Arrange
+	repo, authors := newTestRepositories(&book.Book{ID: 1, AuthorID: 1, Title: "Original"})
+	uc := NewUpdateBookUseCase(repo, authors)
+
+	// Act
+	err := uc.Execute(context.Background(), book.UpdateBookParams{ID: 1, AuthorID: 2, Title: "Updated"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, _ := repo.GetBook(context.Background(), 1); b.Title != "Updated" || b.AuthorID != 2 {
+		t.Errorf("unexpected book after update: %+v", b)
+	}
+}
+
+func TestUpdateBookUseCase_ExecuteNotFound(t *testing.T) {
+	// Arrange
+	repo, authors := newTestRepositories()
+	uc := NewUpdateBookUseCase(repo, authors)
+
+	// Act
+	err := uc.Execute(context.Background(), book.UpdateBookParams{ID: 999, AuthorID: 1, Title: "Ghost"})
+
+	// Assert
+	if !errors.Is(err, apperrors.NotFoundError) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestUpdateBookUseCase_ExecuteMissingAuthor(t *testing.T) {
+	for name, authorID := range map[string]int64{"missing": 4, "soft-deleted": 3} {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo, authors := newTestRepositories(&book.Book{ID: 1, AuthorID: 1, Title: "Original"})
+			uc := NewUpdateBookUseCase(repo, authors)
+
+			// Act
+			err := uc.Execute(context.Background(), book.UpdateBookParams{ID: 1, AuthorID: authorID, Title: "Moved"})
+
+			// Assert
+			if !errors.Is(err, apperrors.NotFoundError) {
+				t.Errorf("expected not found, got %v", err)
+			}
+			if b, _ := repo.GetBook(context.Background(), 1); b.AuthorID != 1 || b.Title != "Original" {
+				t.Errorf("expected the book unchanged, got %+v", b)
+			}
+		})
+	}
+}
@@ -2,4 +2,4 @@ CREATE TABLE authors (
   id   BIGSERIAL PRIMARY KEY,
   name text      NOT NULL,
   bio  text
-);
\ No newline at end of file
+);
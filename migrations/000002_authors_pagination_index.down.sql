@@ -0,0 +1 @@
+DROP INDEX authors_name_id_idx;
@@ -0,0 +1,2 @@
+-- Supports keyset pagination ordered by (name, id).
+CREATE INDEX authors_name_id_idx ON authors (name, id);
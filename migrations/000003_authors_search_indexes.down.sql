@@ -0,0 +1,2 @@
+DROP INDEX authors_bio_fts_idx;
+DROP INDEX authors_name_prefix_idx;
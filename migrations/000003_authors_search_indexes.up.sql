@@ -0,0 +1,5 @@
+-- Supports name prefix searches (name LIKE 'prefix%').
+CREATE INDEX authors_name_prefix_idx ON authors (name text_pattern_ops);
+
+-- Supports full-text search over bio.
+CREATE INDEX authors_bio_fts_idx ON authors USING GIN (to_tsvector('english', coalesce(bio, '')));
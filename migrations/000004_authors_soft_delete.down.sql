@@ -0,0 +1,2 @@
+DROP INDEX authors_deleted_at_idx;
+ALTER TABLE authors DROP COLUMN deleted_at;
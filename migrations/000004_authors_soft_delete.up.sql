@@ -0,0 +1,4 @@
+ALTER TABLE authors ADD COLUMN deleted_at timestamptz;
+
+-- Supports purging authors soft-deleted before a cutoff.
+CREATE INDEX authors_deleted_at_idx ON authors (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE authors ADD COLUMN version integer NOT NULL DEFAULT 1;
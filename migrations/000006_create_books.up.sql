@@ -0,0 +1,9 @@
+CREATE TABLE books (
+  id           BIGSERIAL PRIMARY KEY,
+  author_id    bigint    NOT NULL REFERENCES authors (id) ON DELETE CASCADE,
+  title        text      NOT NULL,
+  isbn         text      UNIQUE,
+  published_at date
+);
+
+CREATE INDEX books_author_id_idx ON books (author_id);
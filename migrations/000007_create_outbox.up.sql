@@ -0,0 +1,15 @@
+CREATE TABLE outbox (
+  id              BIGSERIAL   PRIMARY KEY,
+  aggregate_type  text        NOT NULL,
+  aggregate_id    bigint      NOT NULL,
+  event_type      text        NOT NULL,
+  payload         jsonb       NOT NULL,
+  created_at      timestamptz NOT NULL DEFAULT now(),
+  attempts        integer     NOT NULL DEFAULT 0,
+  next_attempt_at timestamptz NOT NULL DEFAULT now(),
+  last_error      text,
+  published_at    timestamptz
+);
+
+-- The relay only ever scans unpublished messages that are due.
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at, id) WHERE published_at IS NULL;
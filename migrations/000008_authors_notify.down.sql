@@ -0,0 +1,2 @@
+DROP TRIGGER authors_notify ON authors;
+DROP FUNCTION notify_author_change();
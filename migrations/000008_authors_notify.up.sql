@@ -0,0 +1,29 @@
+-- Publishes every change to an author on the author_changes channel. The
+-- payload stays small (NOTIFY payloads are limited to 8000 bytes); listeners
+-- fetch the author if they need more.
+CREATE FUNCTION notify_author_change() RETURNS trigger AS $$
+DECLARE
+  event_type text;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    event_type := 'author.created';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    event_type := 'author.deleted';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    event_type := 'author.restored';
+  ELSE
+    event_type := 'author.updated';
+  END IF;
+
+  PERFORM pg_notify('author_changes', json_build_object(
+    'type', event_type,
+    'author_id', NEW.id,
+    'version', NEW.version
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER authors_notify
+AFTER INSERT OR UPDATE ON authors
+FOR EACH ROW EXECUTE FUNCTION notify_author_change();
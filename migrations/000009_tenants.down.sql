@@ -0,0 +1,43 @@
+CREATE OR REPLACE FUNCTION notify_author_change() RETURNS trigger AS $$
+DECLARE
+  event_type text;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    event_type := 'author.created';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    event_type := 'author.deleted';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    event_type := 'author.restored';
+  ELSE
+    event_type := 'author.updated';
+  END IF;
+
+  PERFORM pg_notify('author_changes', json_build_object(
+    'type', event_type,
+    'author_id', NEW.id,
+    'version', NEW.version
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP POLICY books_tenant_isolation ON books;
+ALTER TABLE books NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE books DISABLE ROW LEVEL SECURITY;
+ALTER TABLE books DROP CONSTRAINT books_tenant_id_isbn_key;
+ALTER TABLE books ADD CONSTRAINT books_isbn_key UNIQUE (isbn);
+ALTER TABLE books DROP CONSTRAINT books_tenant_id_author_id_fkey;
+ALTER TABLE books ADD CONSTRAINT books_author_id_fkey
+  FOREIGN KEY (author_id) REFERENCES authors (id) ON DELETE CASCADE;
+ALTER TABLE books DROP COLUMN tenant_id;
+
+DROP POLICY authors_tenant_isolation ON authors;
+ALTER TABLE authors NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE authors DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX authors_tenant_name_id_idx;
+CREATE INDEX authors_name_id_idx ON authors (name, id);
+
+ALTER TABLE authors DROP CONSTRAINT authors_tenant_id_id_key;
+ALTER TABLE authors DROP CONSTRAINT authors_tenant_id_check;
+ALTER TABLE authors DROP COLUMN tenant_id;
@@ -0,0 +1,63 @@
+-- Scopes authors and books to a tenant (publisher) with row-level security.
+-- Every statement must run in a transaction that first sets app.tenant_id
+-- (SET LOCAL app.tenant_id = '...'); without it no rows are visible and
+-- inserts fail. The pseudo-tenant '*' may read and delete every tenant's
+-- rows but cannot write any. Superusers and BYPASSRLS roles skip these
+-- policies, so the application must connect as an ordinary role.
+ALTER TABLE authors ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE authors ALTER COLUMN tenant_id SET DEFAULT NULLIF(current_setting('app.tenant_id', true), '');
+ALTER TABLE authors ADD CONSTRAINT authors_tenant_id_check CHECK (tenant_id ~ '^[A-Za-z0-9_-]{1,64}$');
+ALTER TABLE authors ADD CONSTRAINT authors_tenant_id_id_key UNIQUE (tenant_id, id);
+
+DROP INDEX authors_name_id_idx;
+CREATE INDEX authors_tenant_name_id_idx ON authors (tenant_id, name, id);
+
+ALTER TABLE authors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE authors FORCE ROW LEVEL SECURITY;
+CREATE POLICY authors_tenant_isolation ON authors
+  USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.tenant_id', true) = '*')
+  WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+-- A book belongs to its author's tenant: the composite foreign key rejects
+-- books referencing another tenant's author, and ISBNs are only unique
+-- within a tenant so they reveal nothing across tenants.
+ALTER TABLE books ADD COLUMN tenant_id text;
+UPDATE books SET tenant_id = 'default';
+ALTER TABLE books ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE books ALTER COLUMN tenant_id SET DEFAULT NULLIF(current_setting('app.tenant_id', true), '');
+ALTER TABLE books DROP CONSTRAINT books_author_id_fkey;
+ALTER TABLE books ADD CONSTRAINT books_tenant_id_author_id_fkey
+  FOREIGN KEY (tenant_id, author_id) REFERENCES authors (tenant_id, id) ON DELETE CASCADE;
+ALTER TABLE books DROP CONSTRAINT books_isbn_key;
+ALTER TABLE books ADD CONSTRAINT books_tenant_id_isbn_key UNIQUE (tenant_id, isbn);
+
+ALTER TABLE books ENABLE ROW LEVEL SECURITY;
+ALTER TABLE books FORCE ROW LEVEL SECURITY;
+CREATE POLICY books_tenant_isolation ON books
+  USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.tenant_id', true) = '*')
+  WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+-- Change notifications carry the tenant so listeners can route them.
+CREATE OR REPLACE FUNCTION notify_author_change() RETURNS trigger AS $$
+DECLARE
+  event_type text;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    event_type := 'author.created';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    event_type := 'author.deleted';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    event_type := 'author.restored';
+  ELSE
+    event_type := 'author.updated';
+  END IF;
+
+  PERFORM pg_notify('author_changes', json_build_object(
+    'type', event_type,
+    'author_id', NEW.id,
+    'version', NEW.version,
+    'tenant_id', NEW.tenant_id
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
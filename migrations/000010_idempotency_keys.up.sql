@@ -0,0 +1,25 @@
+-- Remembers the response to every mutating request sent with an
+-- Idempotency-Key so a retry replays it instead of repeating the change.
+-- A row without a response_status is a request still in progress. Keys are
+-- scoped to a tenant like authors (see 000009).
+CREATE TABLE idempotency_keys (
+  tenant_id        text        NOT NULL DEFAULT NULLIF(current_setting('app.tenant_id', true), '')
+                               CHECK (tenant_id ~ '^[A-Za-z0-9_-]{1,64}$'),
+  key              text        NOT NULL CHECK (char_length(key) BETWEEN 1 AND 255),
+  fingerprint      text        NOT NULL,
+  response_status  integer,
+  response_headers jsonb,
+  response_body    bytea,
+  created_at       timestamptz NOT NULL DEFAULT now(),
+  expires_at       timestamptz NOT NULL,
+  PRIMARY KEY (tenant_id, key)
+);
+
+-- cmd/purge deletes expired keys of every tenant.
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY idempotency_keys_tenant_isolation ON idempotency_keys
+  USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.tenant_id', true) = '*')
+  WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
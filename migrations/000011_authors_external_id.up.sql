@@ -0,0 +1,5 @@
+-- Lets sync jobs address authors by their identifier in an upstream system.
+-- It is optional and unique within a tenant.
+ALTER TABLE authors ADD COLUMN external_id text
+  CONSTRAINT authors_external_id_check CHECK (char_length(external_id) BETWEEN 1 AND 255);
+ALTER TABLE authors ADD CONSTRAINT authors_tenant_id_external_id_key UNIQUE (tenant_id, external_id);
@@ -0,0 +1,11 @@
+// Package migrations embeds the numbered SQL schema migrations. Files are
+// named NNNNNN_title.up.sql / NNNNNN_title.down.sql; sqlc reads the same
+// directory as its schema source and ignores the down files.
+package migrations
+
+import "embed"
+
+// FS holds every migration file.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,104 @@
+-- name: GetAuthor :one
+SELECT * FROM authors
+WHERE id = ? AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetAuthorsByIDs :many
+SELECT * FROM authors
+WHERE id IN (sqlc.slice(ids)) AND deleted_at IS NULL;
+
+-- name: ListAuthors :many
+SELECT * FROM authors
+WHERE deleted_at IS NULL
+ORDER BY name;
+
+-- name: CreateAuthor :execlastid
+-- MySQL has no RETURNING; the repository reads the row back by this ID.
+INSERT INTO authors (
+  name, bio
+) VALUES (
+  ?, ?
+);
+
+-- name: UpsertAuthor :execresult
+-- Creates the author with the given external_id or updates it, bringing it
+-- back if soft-deleted. MySQL reports one affected row for an insert and two
+-- for an update; LAST_INSERT_ID(id) makes an updated row's ID the last insert
+-- ID so the repository can read either back.
+INSERT INTO authors (
+  external_id, name, bio
+) VALUES (
+  ?, ?, ?
+)
+ON DUPLICATE KEY UPDATE
+  name = VALUES(name),
+  bio = VALUES(bio),
+  deleted_at = NULL,
+  version = version + 1,
+  id = LAST_INSERT_ID(id);
+
+-- name: UpdateAuthor :execrows
+-- An expected_version of 0 skips the optimistic concurrency check.
+UPDATE authors
+  set name = sqlc.arg(name),
+  bio = sqlc.arg(bio),
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.arg(expected_version) = 0 OR version = sqlc.arg(expected_version));
+
+-- name: PatchAuthor :execrows
+-- A null name leaves the name unchanged, and so does a false set_bio for the
+-- bio; set_bio with a null bio clears it. An expected_version of 0 skips the
+-- optimistic concurrency check.
+UPDATE authors
+  set name = COALESCE(sqlc.narg(name), name),
+  bio = CASE WHEN CAST(sqlc.arg(set_bio) AS UNSIGNED) = 1 THEN sqlc.narg(bio) ELSE bio END,
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.arg(expected_version) = 0 OR version = sqlc.arg(expected_version));
+
+-- name: DeleteAuthor :execrows
+-- An expected_version of 0 skips the optimistic concurrency check.
+UPDATE authors
+  set deleted_at = sqlc.arg(deleted_at),
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.arg(expected_version) = 0 OR version = sqlc.arg(expected_version));
+
+-- name: RestoreAuthor :execrows
+UPDATE authors
+  set deleted_at = NULL,
+  version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedAuthors :execrows
+DELETE FROM authors
+WHERE deleted_at < sqlc.arg(deleted_before);
+
+-- name: ListAuthorsPage :many
+SELECT * FROM authors
+WHERE (CAST(sqlc.arg(include_deleted) AS UNSIGNED) = 1 OR deleted_at IS NULL)
+  AND (CAST(sqlc.arg(has_cursor) AS UNSIGNED) = 0
+       OR name > sqlc.arg(after_name)
+       OR (name = sqlc.arg(after_name) AND id > sqlc.arg(after_id)))
+ORDER BY name, id
+LIMIT ?;
+
+-- name: SearchAuthors :many
+-- query must be a boolean-mode full-text expression; see mysqlFTSQuery in the
+-- repository.
+SELECT * FROM authors
+WHERE MATCH (bio) AGAINST (sqlc.arg(query) IN BOOLEAN MODE)
+  AND deleted_at IS NULL
+  AND LEFT(name, CHAR_LENGTH(sqlc.arg(name_prefix))) = sqlc.arg(name_prefix)
+  AND COALESCE(CAST(sqlc.narg(has_bio) AS UNSIGNED), COALESCE(bio, '') <> '') = (COALESCE(bio, '') <> '')
+ORDER BY MATCH (bio) AGAINST (sqlc.arg(query) IN BOOLEAN MODE) DESC, name, id
+LIMIT ?;
+
+-- name: FilterAuthors :many
+-- SearchAuthors without a text query.
+SELECT * FROM authors
+WHERE deleted_at IS NULL
+  AND LEFT(name, CHAR_LENGTH(sqlc.arg(name_prefix))) = sqlc.arg(name_prefix)
+  AND COALESCE(CAST(sqlc.narg(has_bio) AS UNSIGNED), COALESCE(bio, '') <> '') = (COALESCE(bio, '') <> '')
+ORDER BY name, id
+LIMIT ?;
@@ -0,0 +1,447 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: query.sql
+
+package mysqldb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+const createAuthor = `-- name: CreateAuthor :execlastid
+INSERT INTO authors (
+  name, bio
+) VALUES (
+  ?, ?
+)
+`
+
+type CreateAuthorParams struct {
+	Name string
+	Bio  sql.NullString
+}
+
+// MySQL has no RETURNING; the repository reads the row back by this ID.
+func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createAuthor, arg.Name, arg.Bio)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const deleteAuthor = `-- name: DeleteAuthor :execrows
+UPDATE authors
+  set deleted_at = ?,
+  version = version + 1
+WHERE id = ? AND deleted_at IS NULL
+  AND (? = 0 OR version = ?)
+`
+
+type DeleteAuthorParams struct {
+	DeletedAt       sql.NullTime
+	ID              int64
+	ExpectedVersion int32
+}
+
+// An expected_version of 0 skips the optimistic concurrency check.
+func (q *Queries) DeleteAuthor(ctx context.Context, arg DeleteAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAuthor,
+		arg.DeletedAt,
+		arg.ID,
+		arg.ExpectedVersion,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const filterAuthors = `-- name: FilterAuthors :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE deleted_at IS NULL
+  AND LEFT(name, CHAR_LENGTH(?)) = ?
+  AND COALESCE(CAST(? AS UNSIGNED), COALESCE(bio, '') <> '') = (COALESCE(bio, '') <> '')
+ORDER BY name, id
+LIMIT ?
+`
+
+type FilterAuthorsParams struct {
+	NamePrefix string
+	HasBio     sql.NullInt64
+	Limit      int32
+}
+
+// SearchAuthors without a text query.
+func (q *Queries) FilterAuthors(ctx context.Context, arg FilterAuthorsParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, filterAuthors,
+		arg.NamePrefix,
+		arg.NamePrefix,
+		arg.HasBio,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuthor = `-- name: GetAuthor :one
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE id = ? AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) GetAuthor(ctx context.Context, id int64) (Author, error) {
+	row := q.db.QueryRowContext(ctx, getAuthor, id)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.Version,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getAuthorsByIDs = `-- name: GetAuthorsByIDs :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]Author, error) {
+	query := getAuthorsByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthors = `-- name: ListAuthors :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE deleted_at IS NULL
+ORDER BY name
+`
+
+func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsPage = `-- name: ListAuthorsPage :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE (CAST(? AS UNSIGNED) = 1 OR deleted_at IS NULL)
+  AND (CAST(? AS UNSIGNED) = 0
+       OR name > ?
+       OR (name = ? AND id > ?))
+ORDER BY name, id
+LIMIT ?
+`
+
+type ListAuthorsPageParams struct {
+	IncludeDeleted int64
+	HasCursor      int64
+	AfterName      string
+	AfterID        int64
+	Limit          int32
+}
+
+func (q *Queries) ListAuthorsPage(ctx context.Context, arg ListAuthorsPageParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsPage,
+		arg.IncludeDeleted,
+		arg.HasCursor,
+		arg.AfterName,
+		arg.AfterName,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const patchAuthor = `-- name: PatchAuthor :execrows
+UPDATE authors
+  set name = COALESCE(?, name),
+  bio = CASE WHEN CAST(? AS UNSIGNED) = 1 THEN ? ELSE bio END,
+  version = version + 1
+WHERE id = ? AND deleted_at IS NULL
+  AND (? = 0 OR version = ?)
+`
+
+type PatchAuthorParams struct {
+	Name            sql.NullString
+	SetBio          int64
+	Bio             sql.NullString
+	ID              int64
+	ExpectedVersion int32
+}
+
+// A null name leaves the name unchanged, and so does a false set_bio for the
+// bio; set_bio with a null bio clears it. An expected_version of 0 skips the
+// optimistic concurrency check.
+func (q *Queries) PatchAuthor(ctx context.Context, arg PatchAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, patchAuthor,
+		arg.Name,
+		arg.SetBio,
+		arg.Bio,
+		arg.ID,
+		arg.ExpectedVersion,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDeletedAuthors = `-- name: PurgeDeletedAuthors :execrows
+DELETE FROM authors
+WHERE deleted_at < ?
+`
+
+func (q *Queries) PurgeDeletedAuthors(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedAuthors, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreAuthor = `-- name: RestoreAuthor :execrows
+UPDATE authors
+  set deleted_at = NULL,
+  version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreAuthor(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreAuthor, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchAuthors = `-- name: SearchAuthors :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE MATCH (bio) AGAINST (? IN BOOLEAN MODE)
+  AND deleted_at IS NULL
+  AND LEFT(name, CHAR_LENGTH(?)) = ?
+  AND COALESCE(CAST(? AS UNSIGNED), COALESCE(bio, '') <> '') = (COALESCE(bio, '') <> '')
+ORDER BY MATCH (bio) AGAINST (? IN BOOLEAN MODE) DESC, name, id
+LIMIT ?
+`
+
+type SearchAuthorsParams struct {
+	Query      string
+	NamePrefix string
+	HasBio     sql.NullInt64
+	Query_2    string
+	Limit      int32
+}
+
+// query must be a boolean-mode full-text expression; see mysqlFTSQuery in the
+// repository.
+func (q *Queries) SearchAuthors(ctx context.Context, arg SearchAuthorsParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, searchAuthors,
+		arg.Query,
+		arg.NamePrefix,
+		arg.NamePrefix,
+		arg.HasBio,
+		arg.Query_2,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAuthor = `-- name: UpdateAuthor :execrows
+UPDATE authors
+  set name = ?,
+  bio = ?,
+  version = version + 1
+WHERE id = ? AND deleted_at IS NULL
+  AND (? = 0 OR version = ?)
+`
+
+type UpdateAuthorParams struct {
+	Name            string
+	Bio             sql.NullString
+	ID              int64
+	ExpectedVersion int32
+}
+
+// An expected_version of 0 skips the optimistic concurrency check.
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateAuthor,
+		arg.Name,
+		arg.Bio,
+		arg.ID,
+		arg.ExpectedVersion,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertAuthor = `-- name: UpsertAuthor :execresult
+INSERT INTO authors (
+  external_id, name, bio
+) VALUES (
+  ?, ?, ?
+)
+ON DUPLICATE KEY UPDATE
+  name = VALUES(name),
+  bio = VALUES(bio),
+  deleted_at = NULL,
+  version = version + 1,
+  id = LAST_INSERT_ID(id)
+`
+
+type UpsertAuthorParams struct {
+	ExternalID sql.NullString
+	Name       string
+	Bio        sql.NullString
+}
+
+// Creates the author with the given external_id or updates it, bringing it
+// back if soft-deleted. MySQL reports one affected row for an insert and two
+// for an update; LAST_INSERT_ID(id) makes an updated row's ID the last insert
+// ID so the repository can read either back.
+func (q *Queries) UpsertAuthor(ctx context.Context, arg UpsertAuthorParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, upsertAuthor, arg.ExternalID, arg.Name, arg.Bio)
+}
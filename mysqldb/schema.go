@@ -0,0 +1,29 @@
+package mysqldb
+
+import _ "embed"
+
+// Schema creates the MySQL tables and indexes. Every statement is idempotent,
+// so it is safe to apply on each start.
+//
+//go:embed schema.sql
+var Schema string
+
+// Upgrade adds a column that Schema gained after the first release to a table
+// created by an older Schema, which CREATE TABLE IF NOT EXISTS leaves as is.
+type Upgrade struct {
+	Table  string
+	Column string
+	// SQL adds Column and any index on it. It runs only when Table exists
+	// without Column, before Schema is applied.
+	SQL string
+}
+
+// Upgrades lists the columns added since the first release, oldest first.
+var Upgrades = []Upgrade{
+	{
+		Table:  "authors",
+		Column: "external_id",
+		SQL: "ALTER TABLE authors ADD COLUMN external_id VARCHAR(255) COLLATE utf8mb4_bin, " +
+			"ADD UNIQUE INDEX authors_external_id_key (external_id)",
+	},
+}
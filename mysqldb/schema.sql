@@ -0,0 +1,18 @@
+-- MySQL schema for the authors table. Like the SQLite schema it is applied
+-- as a whole when the database is opened, so every statement is idempotent.
+-- name uses a binary collation so comparisons, prefix matching and ordering
+-- are case-sensitive as in PostgreSQL.
+
+CREATE TABLE IF NOT EXISTS authors (
+  id         BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name       VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+  bio        TEXT,
+  deleted_at DATETIME(6),
+  version    INT          NOT NULL DEFAULT 1,
+  -- Added after the first release; Upgrades in schema.go adds it and its
+  -- index to older databases before this file is applied.
+  external_id VARCHAR(255) COLLATE utf8mb4_bin,
+  INDEX authors_name_id_idx (name, id),
+  UNIQUE INDEX authors_external_id_key (external_id),
+  FULLTEXT INDEX authors_bio_fts_idx (bio)
+) DEFAULT CHARSET = utf8mb4;
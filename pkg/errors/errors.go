@@ -17,6 +17,18 @@ func (e *DomainError) Error() string {
 	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
 }
 
+// Unwrap returns the underlying error, if any.
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a DomainError with the same code, so that
+// errors.Is(err, NotFoundError) matches any not-found error.
+func (e *DomainError) Is(target error) bool {
+	t, ok := target.(*DomainError)
+	return ok && t.Code == e.Code
+}
+
 // NewDomainError creates a new DomainError.
 func NewDomainError(code, message string, err error) *DomainError {
 	return &DomainError{
@@ -26,15 +38,37 @@ func NewDomainError(code, message string, err error) *DomainError {
 	}
 }
 
+// Error codes used by DomainError.
+const (
+	CodeNotFound           = "NOT_FOUND"
+	CodeValidation         = "VALIDATION_ERROR"
+	CodeDatabase           = "DATABASE_ERROR"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeConflict           = "CONFLICT"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeUnprocessable      = "UNPROCESSABLE"
+)
+
 // NotFoundError represents a "not found" error.
-var NotFoundError = NewDomainError("NOT_FOUND", "resource not found", nil)
+var NotFoundError = NewDomainError(CodeNotFound, "resource not found", nil)
+
+// PreconditionFailedError represents an optimistic concurrency conflict: the
+// resource changed since the caller last read it.
+var PreconditionFailedError = NewDomainError(CodePreconditionFailed, "resource version mismatch", nil)
 
 // ValidationError represents a validation error.
 func ValidationError(message string) *DomainError {
-	return NewDomainError("VALIDATION_ERROR", message, nil)
+	return NewDomainError(CodeValidation, message, nil)
+}
+
+// ConflictError represents a write that conflicts with existing data, such as
+// a duplicate key or a dangling reference.
+func ConflictError(message string, err error) *DomainError {
+	return NewDomainError(CodeConflict, message, err)
 }
 
 // DatabaseError represents a database error.
 func DatabaseError(err error) *DomainError {
-	return NewDomainError("DATABASE_ERROR", "database operation failed", err)
+	return NewDomainError(CodeDatabase, "database operation failed", err)
 }
@@ -1,9 +1,14 @@
 -- name: GetAuthor :one
 SELECT * FROM authors
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetAuthorsByIDs :many
+SELECT * FROM authors
+WHERE id = ANY(sqlc.arg(ids)::bigint[]) AND deleted_at IS NULL;
 
 -- name: ListAuthors :many
 SELECT * FROM authors
+WHERE deleted_at IS NULL
 ORDER BY name;
 
 -- name: CreateAuthor :one
@@ -14,12 +19,207 @@ INSERT INTO authors (
 )
 RETURNING *;
 
--- name: UpdateAuthor :exec
+-- name: UpsertAuthor :one
+-- Creates the author with the given external_id or updates it, bringing it
+-- back if soft-deleted. inserted reports which of the two happened.
+INSERT INTO authors (
+  external_id, name, bio
+) VALUES (
+  sqlc.arg(external_id), sqlc.arg(name), sqlc.arg(bio)
+)
+ON CONFLICT (tenant_id, external_id) DO UPDATE
+  set name = EXCLUDED.name,
+  bio = EXCLUDED.bio,
+  deleted_at = NULL,
+  version = authors.version + 1
+RETURNING *, (xmax = 0) AS inserted;
+
+-- name: BulkCreateAuthors :many
+-- Bulk imports first used pgx CopyFrom. That no longer works: COPY FROM is
+-- not allowed on tables with row-level security, and it returns no rows to
+-- record author.created events for. The rows are sent as arrays instead;
+-- bios[i] is only used where has_bios[i]. The rows come back ordered by
+-- their array position (ord), joined on the ID each took before the insert.
+WITH input AS (
+  SELECT nextval(pg_get_serial_sequence('authors', 'id')) AS id,
+         name, CASE WHEN has_bio THEN bio END AS bio, ord
+  FROM ROWS FROM (unnest(sqlc.arg(names)::text[]),
+                  unnest(sqlc.arg(bios)::text[]),
+                  unnest(sqlc.arg(has_bios)::boolean[]))
+    WITH ORDINALITY AS rows (name, bio, has_bio, ord)
+), inserted AS (
+  INSERT INTO authors (id, name, bio)
+  SELECT id, name, bio FROM input
+  RETURNING *
+)
+SELECT inserted.* FROM inserted JOIN input USING (id)
+ORDER BY input.ord;
+
+-- name: UpdateAuthor :execrows
+-- An expected_version of 0 skips the optimistic concurrency check.
 UPDATE authors
-  set name = $2,
-  bio = $3
-WHERE id = $1;
+  set name = sqlc.arg(name),
+  bio = sqlc.arg(bio),
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.arg(expected_version)::integer = 0 OR version = sqlc.arg(expected_version)::integer);
+
+-- name: PatchAuthor :execrows
+-- A null name leaves the name unchanged, and so does a false set_bio for the
+-- bio; set_bio with a null bio clears it. An expected_version of 0 skips the
+-- optimistic concurrency check.
+UPDATE authors
+  set name = COALESCE(sqlc.narg(name)::text, name),
+  bio = CASE WHEN sqlc.arg(set_bio)::boolean THEN sqlc.narg(bio)::text ELSE bio END,
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.arg(expected_version)::integer = 0 OR version = sqlc.arg(expected_version)::integer);
 
--- name: DeleteAuthor :exec
+-- name: DeleteAuthor :execrows
+-- An expected_version of 0 skips the optimistic concurrency check.
+UPDATE authors
+  set deleted_at = now(),
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.arg(expected_version)::integer = 0 OR version = sqlc.arg(expected_version)::integer);
+
+-- name: RestoreAuthor :execrows
+UPDATE authors
+  set deleted_at = NULL,
+  version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedAuthors :execrows
 DELETE FROM authors
-WHERE id = $1;
\ No newline at end of file
+WHERE deleted_at < sqlc.arg(deleted_before);
+
+-- name: ListAuthorsPage :many
+SELECT * FROM authors
+WHERE (sqlc.arg(include_deleted)::boolean OR deleted_at IS NULL)
+  AND (NOT sqlc.arg(has_cursor)::boolean
+       OR (name, id) > (sqlc.arg(after_name)::text, sqlc.arg(after_id)::bigint))
+ORDER BY name, id
+LIMIT sqlc.arg(page_size);
+
+-- name: SearchAuthors :many
+SELECT * FROM authors
+WHERE deleted_at IS NULL
+  AND (sqlc.arg(query)::text = ''
+       OR to_tsvector('english', coalesce(bio, '')) @@ websearch_to_tsquery('english', sqlc.arg(query)::text))
+  AND (sqlc.arg(name_prefix)::text = '' OR name LIKE sqlc.arg(name_prefix)::text || '%')
+  AND (sqlc.narg(has_bio)::boolean IS NULL OR (coalesce(bio, '') <> '') = sqlc.narg(has_bio)::boolean)
+ORDER BY ts_rank(to_tsvector('english', coalesce(bio, '')), websearch_to_tsquery('english', sqlc.arg(query)::text)) DESC,
+         name, id
+LIMIT sqlc.arg(result_limit);
+
+-- name: GetBook :one
+SELECT * FROM books
+WHERE id = $1 LIMIT 1;
+
+-- name: ListBooks :many
+SELECT * FROM books
+ORDER BY title, id;
+
+-- name: ListBooksByAuthor :many
+SELECT * FROM books
+WHERE author_id = $1
+ORDER BY published_at NULLS LAST, title, id;
+
+-- name: CreateBook :one
+INSERT INTO books (
+  author_id, title, isbn, published_at
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: UpdateBook :execrows
+UPDATE books
+  set author_id = $2,
+  title = $3,
+  isbn = $4,
+  published_at = $5
+WHERE id = $1;
+
+-- name: DeleteBook :execrows
+DELETE FROM books
+WHERE id = $1;
+
+-- name: AppendOutbox :exec
+INSERT INTO outbox (
+  aggregate_type, aggregate_id, event_type, payload, created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: ClaimOutbox :many
+-- Leases due messages by moving their next attempt to lease_until, so
+-- concurrent relays skip them while they are delivered outside any
+-- transaction. SKIP LOCKED keeps two claims from waiting on each other.
+WITH claimed AS (
+  UPDATE outbox
+    set next_attempt_at = sqlc.arg(lease_until)
+  WHERE id IN (
+    SELECT due.id FROM outbox AS due
+    WHERE due.published_at IS NULL AND due.next_attempt_at <= sqlc.arg(now)
+    ORDER BY due.id
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING *
+)
+SELECT * FROM claimed
+ORDER BY id;
+
+-- name: MarkOutboxPublished :exec
+UPDATE outbox
+  set published_at = sqlc.arg(published_at)
+WHERE id = sqlc.arg(id);
+
+-- name: MarkOutboxFailed :exec
+UPDATE outbox
+  set attempts = attempts + 1,
+  next_attempt_at = sqlc.arg(next_attempt_at),
+  last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
+-- name: ReserveIdempotencyKey :one
+-- Claims a key for the current tenant, taking over an expired one. Returns
+-- no rows while the key is held by an unexpired request.
+INSERT INTO idempotency_keys (
+  key, fingerprint, created_at, expires_at
+) VALUES (
+  sqlc.arg(key), sqlc.arg(fingerprint), sqlc.arg(now), sqlc.arg(expires_at)
+)
+ON CONFLICT (tenant_id, key) DO UPDATE
+  set fingerprint = EXCLUDED.fingerprint,
+  response_status = NULL,
+  response_headers = NULL,
+  response_body = NULL,
+  created_at = EXCLUDED.created_at,
+  expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE key = $1;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+  set response_status = sqlc.arg(response_status),
+  response_headers = sqlc.arg(response_headers),
+  response_body = sqlc.arg(response_body)
+WHERE key = sqlc.arg(key);
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE key = $1 AND response_status IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= $1;
+
+-- name: PurgePublishedOutbox :execrows
+DELETE FROM outbox
+WHERE published_at < sqlc.arg(published_before);
@@ -0,0 +1,102 @@
+-- name: GetAuthor :one
+SELECT * FROM authors
+WHERE id = ? AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetAuthorsByIDs :many
+SELECT * FROM authors
+WHERE id IN (sqlc.slice(ids)) AND deleted_at IS NULL;
+
+-- name: ListAuthors :many
+SELECT * FROM authors
+WHERE deleted_at IS NULL
+ORDER BY name;
+
+-- name: CreateAuthor :one
+INSERT INTO authors (
+  name, bio
+) VALUES (
+  ?, ?
+)
+RETURNING *;
+
+-- name: UpsertAuthor :one
+-- Creates the author with the given external_id or updates it, bringing it
+-- back if soft-deleted. A created row is the only one with version 1.
+INSERT INTO authors (
+  external_id, name, bio
+) VALUES (
+  sqlc.arg(external_id), sqlc.arg(name), sqlc.arg(bio)
+)
+ON CONFLICT (external_id) DO UPDATE
+  set name = excluded.name,
+  bio = excluded.bio,
+  deleted_at = NULL,
+  version = version + 1
+RETURNING *;
+
+-- name: UpdateAuthor :execrows
+-- An expected_version of 0 skips the optimistic concurrency check.
+UPDATE authors
+  set name = sqlc.arg(name),
+  bio = sqlc.arg(bio),
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (CAST(sqlc.arg(expected_version) AS INTEGER) = 0 OR version = CAST(sqlc.arg(expected_version) AS INTEGER));
+
+-- name: PatchAuthor :execrows
+-- A null name leaves the name unchanged, and so does a false set_bio for the
+-- bio; set_bio with a null bio clears it. An expected_version of 0 skips the
+-- optimistic concurrency check.
+UPDATE authors
+  set name = COALESCE(sqlc.narg(name), name),
+  bio = CASE WHEN CAST(sqlc.arg(set_bio) AS BOOLEAN) THEN sqlc.narg(bio) ELSE bio END,
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (CAST(sqlc.arg(expected_version) AS INTEGER) = 0 OR version = CAST(sqlc.arg(expected_version) AS INTEGER));
+
+-- name: DeleteAuthor :execrows
+-- An expected_version of 0 skips the optimistic concurrency check.
+UPDATE authors
+  set deleted_at = sqlc.arg(deleted_at),
+  version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (CAST(sqlc.arg(expected_version) AS INTEGER) = 0 OR version = CAST(sqlc.arg(expected_version) AS INTEGER));
+
+-- name: RestoreAuthor :execrows
+UPDATE authors
+  set deleted_at = NULL,
+  version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedAuthors :execrows
+DELETE FROM authors
+WHERE deleted_at < sqlc.arg(deleted_before);
+
+-- name: ListAuthorsPage :many
+SELECT * FROM authors
+WHERE (CAST(sqlc.arg(include_deleted) AS BOOLEAN) OR deleted_at IS NULL)
+  AND (CAST(sqlc.arg(has_cursor) AS BOOLEAN) = 0
+       OR name > CAST(sqlc.arg(after_name) AS TEXT)
+       OR (name = CAST(sqlc.arg(after_name) AS TEXT) AND id > CAST(sqlc.arg(after_id) AS INTEGER)))
+ORDER BY name, id
+LIMIT sqlc.arg(page_size);
+
+-- name: SearchAuthors :many
+-- query must be a valid FTS5 expression; see ftsQuery in the repository.
+SELECT authors.* FROM authors
+JOIN authors_fts ON authors_fts.rowid = authors.id
+WHERE authors_fts.bio MATCH CAST(sqlc.arg(query) AS TEXT)
+  AND authors.deleted_at IS NULL
+  AND substr(authors.name, 1, length(CAST(sqlc.arg(name_prefix) AS TEXT))) = CAST(sqlc.arg(name_prefix) AS TEXT)
+  AND (sqlc.narg(has_bio) IS NULL OR (coalesce(authors.bio, '') <> '') = CAST(sqlc.narg(has_bio) AS BOOLEAN))
+ORDER BY authors_fts.rank, authors.name, authors.id
+LIMIT sqlc.arg(result_limit);
+
+-- name: FilterAuthors :many
+-- SearchAuthors without a text query.
+SELECT * FROM authors
+WHERE deleted_at IS NULL
+  AND substr(name, 1, length(CAST(sqlc.arg(name_prefix) AS TEXT))) = CAST(sqlc.arg(name_prefix) AS TEXT)
+  AND (sqlc.narg(has_bio) IS NULL OR (coalesce(bio, '') <> '') = CAST(sqlc.narg(has_bio) AS BOOLEAN))
+ORDER BY name, id
+LIMIT sqlc.arg(result_limit);
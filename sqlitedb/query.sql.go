@@ -0,0 +1,445 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: query.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+const createAuthor = `-- name: CreateAuthor :one
+INSERT INTO authors (
+  name, bio
+) VALUES (
+  ?, ?
+)
+RETURNING id, name, bio, deleted_at, version, external_id
+`
+
+type CreateAuthorParams struct {
+	Name string
+	Bio  sql.NullString
+}
+
+func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error) {
+	row := q.db.QueryRowContext(ctx, createAuthor, arg.Name, arg.Bio)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.Version,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const deleteAuthor = `-- name: DeleteAuthor :execrows
+UPDATE authors
+  set deleted_at = ?1,
+  version = version + 1
+WHERE id = ?2 AND deleted_at IS NULL
+  AND (CAST(?3 AS INTEGER) = 0 OR version = CAST(?3 AS INTEGER))
+`
+
+type DeleteAuthorParams struct {
+	DeletedAt       sql.NullTime
+	ID              int64
+	ExpectedVersion int64
+}
+
+// An expected_version of 0 skips the optimistic concurrency check.
+func (q *Queries) DeleteAuthor(ctx context.Context, arg DeleteAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAuthor, arg.DeletedAt, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const filterAuthors = `-- name: FilterAuthors :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE deleted_at IS NULL
+  AND substr(name, 1, length(CAST(?1 AS TEXT))) = CAST(?1 AS TEXT)
+  AND (?2 IS NULL OR (coalesce(bio, '') <> '') = CAST(?2 AS BOOLEAN))
+ORDER BY name, id
+LIMIT ?3
+`
+
+type FilterAuthorsParams struct {
+	NamePrefix  string
+	HasBio      interface{}
+	ResultLimit int64
+}
+
+// SearchAuthors without a text query.
+func (q *Queries) FilterAuthors(ctx context.Context, arg FilterAuthorsParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, filterAuthors, arg.NamePrefix, arg.HasBio, arg.ResultLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuthor = `-- name: GetAuthor :one
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE id = ? AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) GetAuthor(ctx context.Context, id int64) (Author, error) {
+	row := q.db.QueryRowContext(ctx, getAuthor, id)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.Version,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getAuthorsByIDs = `-- name: GetAuthorsByIDs :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]Author, error) {
+	query := getAuthorsByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthors = `-- name: ListAuthors :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE deleted_at IS NULL
+ORDER BY name
+`
+
+func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsPage = `-- name: ListAuthorsPage :many
+SELECT id, name, bio, deleted_at, version, external_id FROM authors
+WHERE (CAST(?1 AS BOOLEAN) OR deleted_at IS NULL)
+  AND (CAST(?2 AS BOOLEAN) = 0
+       OR name > CAST(?3 AS TEXT)
+       OR (name = CAST(?3 AS TEXT) AND id > CAST(?4 AS INTEGER)))
+ORDER BY name, id
+LIMIT ?5
+`
+
+type ListAuthorsPageParams struct {
+	IncludeDeleted bool
+	HasCursor      bool
+	AfterName      string
+	AfterID        int64
+	PageSize       int64
+}
+
+func (q *Queries) ListAuthorsPage(ctx context.Context, arg ListAuthorsPageParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsPage,
+		arg.IncludeDeleted,
+		arg.HasCursor,
+		arg.AfterName,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const patchAuthor = `-- name: PatchAuthor :execrows
+UPDATE authors
+  set name = COALESCE(?1, name),
+  bio = CASE WHEN CAST(?2 AS BOOLEAN) THEN ?3 ELSE bio END,
+  version = version + 1
+WHERE id = ?4 AND deleted_at IS NULL
+  AND (CAST(?5 AS INTEGER) = 0 OR version = CAST(?5 AS INTEGER))
+`
+
+type PatchAuthorParams struct {
+	Name            sql.NullString
+	SetBio          bool
+	Bio             sql.NullString
+	ID              int64
+	ExpectedVersion int64
+}
+
+// A null name leaves the name unchanged, and so does a false set_bio for the
+// bio; set_bio with a null bio clears it. An expected_version of 0 skips the
+// optimistic concurrency check.
+func (q *Queries) PatchAuthor(ctx context.Context, arg PatchAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, patchAuthor,
+		arg.Name,
+		arg.SetBio,
+		arg.Bio,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDeletedAuthors = `-- name: PurgeDeletedAuthors :execrows
+DELETE FROM authors
+WHERE deleted_at < ?1
+`
+
+func (q *Queries) PurgeDeletedAuthors(ctx context.Context, deletedBefore sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedAuthors, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreAuthor = `-- name: RestoreAuthor :execrows
+UPDATE authors
+  set deleted_at = NULL,
+  version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreAuthor(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreAuthor, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchAuthors = `-- name: SearchAuthors :many
+SELECT authors.id, authors.name, authors.bio, authors.deleted_at, authors.version, authors.external_id FROM authors
+JOIN authors_fts ON authors_fts.rowid = authors.id
+WHERE authors_fts.bio MATCH CAST(?1 AS TEXT)
+  AND authors.deleted_at IS NULL
+  AND substr(authors.name, 1, length(CAST(?2 AS TEXT))) = CAST(?2 AS TEXT)
+  AND (?3 IS NULL OR (coalesce(authors.bio, '') <> '') = CAST(?3 AS BOOLEAN))
+ORDER BY authors_fts.rank, authors.name, authors.id
+LIMIT ?4
+`
+
+type SearchAuthorsParams struct {
+	Query       string
+	NamePrefix  string
+	HasBio      interface{}
+	ResultLimit int64
+}
+
+// query must be a valid FTS5 expression; see ftsQuery in the repository.
+func (q *Queries) SearchAuthors(ctx context.Context, arg SearchAuthorsParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, searchAuthors,
+		arg.Query,
+		arg.NamePrefix,
+		arg.HasBio,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAuthor = `-- name: UpdateAuthor :execrows
+UPDATE authors
+  set name = ?1,
+  bio = ?2,
+  version = version + 1
+WHERE id = ?3 AND deleted_at IS NULL
+  AND (CAST(?4 AS INTEGER) = 0 OR version = CAST(?4 AS INTEGER))
+`
+
+type UpdateAuthorParams struct {
+	Name            string
+	Bio             sql.NullString
+	ID              int64
+	ExpectedVersion int64
+}
+
+// An expected_version of 0 skips the optimistic concurrency check.
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateAuthor,
+		arg.Name,
+		arg.Bio,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertAuthor = `-- name: UpsertAuthor :one
+INSERT INTO authors (
+  external_id, name, bio
+) VALUES (
+  ?1, ?2, ?3
+)
+ON CONFLICT (external_id) DO UPDATE
+  set name = excluded.name,
+  bio = excluded.bio,
+  deleted_at = NULL,
+  version = version + 1
+RETURNING id, name, bio, deleted_at, version, external_id
+`
+
+type UpsertAuthorParams struct {
+	ExternalID sql.NullString
+	Name       string
+	Bio        sql.NullString
+}
+
+// Creates the author with the given external_id or updates it, bringing it
+// back if soft-deleted. A created row is the only one with version 1.
+func (q *Queries) UpsertAuthor(ctx context.Context, arg UpsertAuthorParams) (Author, error) {
+	row := q.db.QueryRowContext(ctx, upsertAuthor, arg.ExternalID, arg.Name, arg.Bio)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.Version,
+		&i.ExternalID,
+	)
+	return i, err
+}
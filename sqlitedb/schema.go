@@ -0,0 +1,28 @@
+package sqlitedb
+
+import _ "embed"
+
+// Schema creates the SQLite tables, indexes and triggers. Every statement is
+// idempotent, so it is safe to apply on each start.
+//
+//go:embed schema.sql
+var Schema string
+
+// Upgrade adds a column that Schema gained after the first release to a table
+// created by an older Schema, which CREATE TABLE IF NOT EXISTS leaves as is.
+type Upgrade struct {
+	Table  string
+	Column string
+	// SQL adds Column. It runs only when Table exists without Column, before
+	// Schema is applied, so Schema may index the column.
+	SQL string
+}
+
+// Upgrades lists the columns added since the first release, oldest first.
+var Upgrades = []Upgrade{
+	{
+		Table:  "authors",
+		Column: "external_id",
+		SQL:    "ALTER TABLE authors ADD COLUMN external_id TEXT CHECK (length(external_id) BETWEEN 1 AND 255)",
+	},
+}
@@ -0,0 +1,39 @@
+-- SQLite schema for the authors table. Unlike the PostgreSQL schema it is not
+-- versioned through migrations/: every statement is idempotent and the whole
+-- file is applied when the database is opened.
+
+CREATE TABLE IF NOT EXISTS authors (
+  id         INTEGER  PRIMARY KEY AUTOINCREMENT,
+  name       TEXT     NOT NULL,
+  bio        TEXT,
+  deleted_at DATETIME,
+  version    INTEGER  NOT NULL DEFAULT 1,
+  -- Added after the first release; Upgrades in schema.go adds it to older
+  -- database files before this file is applied.
+  external_id TEXT    CHECK (length(external_id) BETWEEN 1 AND 255)
+);
+
+CREATE INDEX IF NOT EXISTS authors_name_id_idx ON authors (name, id);
+CREATE UNIQUE INDEX IF NOT EXISTS authors_external_id_key ON authors (external_id);
+
+-- External-content FTS5 index over bio, kept in sync by the triggers below.
+-- The porter tokenizer approximates PostgreSQL's english text search config.
+CREATE VIRTUAL TABLE IF NOT EXISTS authors_fts USING fts5(
+  bio,
+  content='authors',
+  content_rowid='id',
+  tokenize='porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS authors_fts_insert AFTER INSERT ON authors BEGIN
+  INSERT INTO authors_fts (rowid, bio) VALUES (new.id, new.bio);
+END;
+
+CREATE TRIGGER IF NOT EXISTS authors_fts_delete AFTER DELETE ON authors BEGIN
+  INSERT INTO authors_fts (authors_fts, rowid, bio) VALUES ('delete', old.id, old.bio);
+END;
+
+CREATE TRIGGER IF NOT EXISTS authors_fts_update AFTER UPDATE OF bio ON authors BEGIN
+  INSERT INTO authors_fts (authors_fts, rowid, bio) VALUES ('delete', old.id, old.bio);
+  INSERT INTO authors_fts (rowid, bio) VALUES (new.id, new.bio);
+END;
@@ -9,7 +9,44 @@ import (
 )
 
 type Author struct {
-	ID   int64
-	Name string
-	Bio  pgtype.Text
+	ID         int64
+	Name       string
+	Bio        pgtype.Text
+	DeletedAt  pgtype.Timestamptz
+	Version    int32
+	TenantID   string
+	ExternalID pgtype.Text
+}
+
+type Book struct {
+	ID          int64
+	AuthorID    int64
+	Title       string
+	Isbn        pgtype.Text
+	PublishedAt pgtype.Date
+	TenantID    string
+}
+
+type IdempotencyKey struct {
+	TenantID        string
+	Key             string
+	Fingerprint     string
+	ResponseStatus  pgtype.Int4
+	ResponseHeaders []byte
+	ResponseBody    []byte
+	CreatedAt       pgtype.Timestamptz
+	ExpiresAt       pgtype.Timestamptz
+}
+
+type Outbox struct {
+	ID            int64
+	AggregateType string
+	AggregateID   int64
+	EventType     string
+	Payload       []byte
+	CreatedAt     pgtype.Timestamptz
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamptz
+	LastError     pgtype.Text
+	PublishedAt   pgtype.Timestamptz
 }
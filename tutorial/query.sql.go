@@ -11,13 +11,201 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const appendOutbox = `-- name: AppendOutbox :exec
+INSERT INTO outbox (
+  aggregate_type, aggregate_id, event_type, payload, created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+`
+
+type AppendOutboxParams struct {
+	AggregateType string
+	AggregateID   int64
+	EventType     string
+	Payload       []byte
+	CreatedAt     pgtype.Timestamptz
+}
+
+func (q *Queries) AppendOutbox(ctx context.Context, arg AppendOutboxParams) error {
+	_, err := q.db.Exec(ctx, appendOutbox,
+		arg.AggregateType,
+		arg.AggregateID,
+		arg.EventType,
+		arg.Payload,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const bulkCreateAuthors = `-- name: BulkCreateAuthors :many
+WITH input AS (
+  SELECT nextval(pg_get_serial_sequence('authors', 'id')) AS id,
+         name, CASE WHEN has_bio THEN bio END AS bio, ord
+  FROM ROWS FROM (unnest($1::text[]),
+                  unnest($2::text[]),
+                  unnest($3::boolean[]))
+    WITH ORDINALITY AS rows (name, bio, has_bio, ord)
+), inserted AS (
+  INSERT INTO authors (id, name, bio)
+  SELECT id, name, bio FROM input
+  RETURNING id, name, bio, deleted_at, version, tenant_id, external_id
+)
+SELECT inserted.id, inserted.name, inserted.bio, inserted.deleted_at, inserted.version, inserted.tenant_id, inserted.external_id FROM inserted JOIN input USING (id)
+ORDER BY input.ord
+`
+
+type BulkCreateAuthorsParams struct {
+	Names   []string
+	Bios    []string
+	HasBios []bool
+}
+
+type BulkCreateAuthorsRow struct {
+	ID         int64
+	Name       string
+	Bio        pgtype.Text
+	DeletedAt  pgtype.Timestamptz
+	Version    int32
+	TenantID   string
+	ExternalID pgtype.Text
+}
+
+// Bulk imports first used pgx CopyFrom. That no longer works: COPY FROM is
+// not allowed on tables with row-level security, and it returns no rows to
+// record author.created events for. The rows are sent as arrays instead;
+// bios[i] is only used where has_bios[i]. The rows come back ordered by
+// their array position (ord), joined on the ID each took before the insert.
+func (q *Queries) BulkCreateAuthors(ctx context.Context, arg BulkCreateAuthorsParams) ([]BulkCreateAuthorsRow, error) {
+	rows, err := q.db.Query(ctx, bulkCreateAuthors, arg.Names, arg.Bios, arg.HasBios)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BulkCreateAuthorsRow
+	for rows.Next() {
+		var i BulkCreateAuthorsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimOutbox = `-- name: ClaimOutbox :many
+WITH claimed AS (
+  UPDATE outbox
+    set next_attempt_at = $1
+  WHERE id IN (
+    SELECT due.id FROM outbox AS due
+    WHERE due.published_at IS NULL AND due.next_attempt_at <= $2
+    ORDER BY due.id
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, attempts, next_attempt_at, last_error, published_at
+)
+SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at, attempts, next_attempt_at, last_error, published_at FROM claimed
+ORDER BY id
+`
+
+type ClaimOutboxParams struct {
+	LeaseUntil pgtype.Timestamptz
+	Now        pgtype.Timestamptz
+	BatchSize  int32
+}
+
+type ClaimOutboxRow struct {
+	ID            int64
+	AggregateType string
+	AggregateID   int64
+	EventType     string
+	Payload       []byte
+	CreatedAt     pgtype.Timestamptz
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamptz
+	LastError     pgtype.Text
+	PublishedAt   pgtype.Timestamptz
+}
+
+// Leases due messages by moving their next attempt to lease_until, so
+// concurrent relays skip them while they are delivered outside any
+// transaction. SKIP LOCKED keeps two claims from waiting on each other.
+func (q *Queries) ClaimOutbox(ctx context.Context, arg ClaimOutboxParams) ([]ClaimOutboxRow, error) {
+	rows, err := q.db.Query(ctx, claimOutbox, arg.LeaseUntil, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimOutboxRow
+	for rows.Next() {
+		var i ClaimOutboxRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+  set response_status = $1,
+  response_headers = $2,
+  response_body = $3
+WHERE key = $4
+`
+
+type CompleteIdempotencyKeyParams struct {
+	ResponseStatus  pgtype.Int4
+	ResponseHeaders []byte
+	ResponseBody    []byte
+	Key             string
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, completeIdempotencyKey,
+		arg.ResponseStatus,
+		arg.ResponseHeaders,
+		arg.ResponseBody,
+		arg.Key,
+	)
+	return err
+}
+
 const createAuthor = `-- name: CreateAuthor :one
 INSERT INTO authors (
   name, bio
 ) VALUES (
   $1, $2
 )
-RETURNING id, name, bio
+RETURNING id, name, bio, deleted_at, version, tenant_id, external_id
 `
 
 type CreateAuthorParams struct {
@@ -28,34 +216,197 @@ type CreateAuthorParams struct {
 func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error) {
 	row := q.db.QueryRow(ctx, createAuthor, arg.Name, arg.Bio)
 	var i Author
-	err := row.Scan(&i.ID, &i.Name, &i.Bio)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+		&i.ExternalID,
+	)
 	return i, err
 }
 
-const deleteAuthor = `-- name: DeleteAuthor :exec
-DELETE FROM authors
+const createBook = `-- name: CreateBook :one
+INSERT INTO books (
+  author_id, title, isbn, published_at
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, author_id, title, isbn, published_at, tenant_id
+`
+
+type CreateBookParams struct {
+	AuthorID    int64
+	Title       string
+	Isbn        pgtype.Text
+	PublishedAt pgtype.Date
+}
+
+func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, error) {
+	row := q.db.QueryRow(ctx, createBook,
+		arg.AuthorID,
+		arg.Title,
+		arg.Isbn,
+		arg.PublishedAt,
+	)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Isbn,
+		&i.PublishedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const deleteAuthor = `-- name: DeleteAuthor :execrows
+UPDATE authors
+  set deleted_at = now(),
+  version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($2::integer = 0 OR version = $2::integer)
+`
+
+type DeleteAuthorParams struct {
+	ID              int64
+	ExpectedVersion int32
+}
+
+// An expected_version of 0 skips the optimistic concurrency check.
+func (q *Queries) DeleteAuthor(ctx context.Context, arg DeleteAuthorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAuthor, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteBook = `-- name: DeleteBook :execrows
+DELETE FROM books
 WHERE id = $1
 `
 
-func (q *Queries) DeleteAuthor(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteAuthor, id)
-	return err
+func (q *Queries) DeleteBook(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBook, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= $1
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredIdempotencyKeys, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getAuthor = `-- name: GetAuthor :one
-SELECT id, name, bio FROM authors
-WHERE id = $1 LIMIT 1
+SELECT id, name, bio, deleted_at, version, tenant_id, external_id FROM authors
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetAuthor(ctx context.Context, id int64) (Author, error) {
 	row := q.db.QueryRow(ctx, getAuthor, id)
 	var i Author
-	err := row.Scan(&i.ID, &i.Name, &i.Bio)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getAuthorsByIDs = `-- name: GetAuthorsByIDs :many
+SELECT id, name, bio, deleted_at, version, tenant_id, external_id FROM authors
+WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetAuthorsByIDs(ctx context.Context, ids []int64) ([]Author, error) {
+	rows, err := q.db.Query(ctx, getAuthorsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBook = `-- name: GetBook :one
+SELECT id, author_id, title, isbn, published_at, tenant_id FROM books
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetBook(ctx context.Context, id int64) (Book, error) {
+	row := q.db.QueryRow(ctx, getBook, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Isbn,
+		&i.PublishedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT tenant_id, key, fingerprint, response_status, response_headers, response_body, created_at, expires_at FROM idempotency_keys
+WHERE key = $1
+`
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, key string) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.TenantID,
+		&i.Key,
+		&i.Fingerprint,
+		&i.ResponseStatus,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
 	return i, err
 }
 
 const listAuthors = `-- name: ListAuthors :many
-SELECT id, name, bio FROM authors
+SELECT id, name, bio, deleted_at, version, tenant_id, external_id FROM authors
+WHERE deleted_at IS NULL
 ORDER BY name
 `
 
@@ -68,7 +419,66 @@ func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
 	var items []Author
 	for rows.Next() {
 		var i Author
-		if err := rows.Scan(&i.ID, &i.Name, &i.Bio); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsPage = `-- name: ListAuthorsPage :many
+SELECT id, name, bio, deleted_at, version, tenant_id, external_id FROM authors
+WHERE ($1::boolean OR deleted_at IS NULL)
+  AND (NOT $2::boolean
+       OR (name, id) > ($3::text, $4::bigint))
+ORDER BY name, id
+LIMIT $5
+`
+
+type ListAuthorsPageParams struct {
+	IncludeDeleted bool
+	HasCursor      bool
+	AfterName      string
+	AfterID        int64
+	PageSize       int32
+}
+
+func (q *Queries) ListAuthorsPage(ctx context.Context, arg ListAuthorsPageParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsPage,
+		arg.IncludeDeleted,
+		arg.HasCursor,
+		arg.AfterName,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+			&i.ExternalID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -79,20 +489,396 @@ func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
 	return items, nil
 }
 
-const updateAuthor = `-- name: UpdateAuthor :exec
+const listBooks = `-- name: ListBooks :many
+SELECT id, author_id, title, isbn, published_at, tenant_id FROM books
+ORDER BY title, id
+`
+
+func (q *Queries) ListBooks(ctx context.Context) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.Title,
+			&i.Isbn,
+			&i.PublishedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByAuthor = `-- name: ListBooksByAuthor :many
+SELECT id, author_id, title, isbn, published_at, tenant_id FROM books
+WHERE author_id = $1
+ORDER BY published_at NULLS LAST, title, id
+`
+
+func (q *Queries) ListBooksByAuthor(ctx context.Context, authorID int64) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByAuthor, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.Title,
+			&i.Isbn,
+			&i.PublishedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxFailed = `-- name: MarkOutboxFailed :exec
+UPDATE outbox
+  set attempts = attempts + 1,
+  next_attempt_at = $1,
+  last_error = $2
+WHERE id = $3
+`
+
+type MarkOutboxFailedParams struct {
+	NextAttemptAt pgtype.Timestamptz
+	LastError     pgtype.Text
+	ID            int64
+}
+
+func (q *Queries) MarkOutboxFailed(ctx context.Context, arg MarkOutboxFailedParams) error {
+	_, err := q.db.Exec(ctx, markOutboxFailed, arg.NextAttemptAt, arg.LastError, arg.ID)
+	return err
+}
+
+const markOutboxPublished = `-- name: MarkOutboxPublished :exec
+UPDATE outbox
+  set published_at = $1
+WHERE id = $2
+`
+
+type MarkOutboxPublishedParams struct {
+	PublishedAt pgtype.Timestamptz
+	ID          int64
+}
+
+func (q *Queries) MarkOutboxPublished(ctx context.Context, arg MarkOutboxPublishedParams) error {
+	_, err := q.db.Exec(ctx, markOutboxPublished, arg.PublishedAt, arg.ID)
+	return err
+}
+
+const patchAuthor = `-- name: PatchAuthor :execrows
 UPDATE authors
-  set name = $2,
-  bio = $3
-WHERE id = $1
+  set name = COALESCE($1::text, name),
+  bio = CASE WHEN $2::boolean THEN $3::text ELSE bio END,
+  version = version + 1
+WHERE id = $4 AND deleted_at IS NULL
+  AND ($5::integer = 0 OR version = $5::integer)
 `
 
-type UpdateAuthorParams struct {
-	ID   int64
-	Name string
-	Bio  pgtype.Text
+type PatchAuthorParams struct {
+	Name            pgtype.Text
+	SetBio          bool
+	Bio             pgtype.Text
+	ID              int64
+	ExpectedVersion int32
 }
 
-func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) error {
-	_, err := q.db.Exec(ctx, updateAuthor, arg.ID, arg.Name, arg.Bio)
+// A null name leaves the name unchanged, and so does a false set_bio for the
+// bio; set_bio with a null bio clears it. An expected_version of 0 skips the
+// optimistic concurrency check.
+func (q *Queries) PatchAuthor(ctx context.Context, arg PatchAuthorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, patchAuthor,
+		arg.Name,
+		arg.SetBio,
+		arg.Bio,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeletedAuthors = `-- name: PurgeDeletedAuthors :execrows
+DELETE FROM authors
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedAuthors(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedAuthors, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgePublishedOutbox = `-- name: PurgePublishedOutbox :execrows
+DELETE FROM outbox
+WHERE published_at < $1
+`
+
+func (q *Queries) PurgePublishedOutbox(ctx context.Context, publishedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgePublishedOutbox, publishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE key = $1 AND response_status IS NULL
+`
+
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, releaseIdempotencyKey, key)
 	return err
 }
+
+const reserveIdempotencyKey = `-- name: ReserveIdempotencyKey :one
+INSERT INTO idempotency_keys (
+  key, fingerprint, created_at, expires_at
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (tenant_id, key) DO UPDATE
+  set fingerprint = EXCLUDED.fingerprint,
+  response_status = NULL,
+  response_headers = NULL,
+  response_body = NULL,
+  created_at = EXCLUDED.created_at,
+  expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+RETURNING tenant_id, key, fingerprint, response_status, response_headers, response_body, created_at, expires_at
+`
+
+type ReserveIdempotencyKeyParams struct {
+	Key         string
+	Fingerprint string
+	Now         pgtype.Timestamptz
+	ExpiresAt   pgtype.Timestamptz
+}
+
+// Claims a key for the current tenant, taking over an expired one. Returns
+// no rows while the key is held by an unexpired request.
+func (q *Queries) ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, reserveIdempotencyKey,
+		arg.Key,
+		arg.Fingerprint,
+		arg.Now,
+		arg.ExpiresAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.TenantID,
+		&i.Key,
+		&i.Fingerprint,
+		&i.ResponseStatus,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const restoreAuthor = `-- name: RestoreAuthor :execrows
+UPDATE authors
+  set deleted_at = NULL,
+  version = version + 1
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreAuthor(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreAuthor, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchAuthors = `-- name: SearchAuthors :many
+SELECT id, name, bio, deleted_at, version, tenant_id, external_id FROM authors
+WHERE deleted_at IS NULL
+  AND ($1::text = ''
+       OR to_tsvector('english', coalesce(bio, '')) @@ websearch_to_tsquery('english', $1::text))
+  AND ($2::text = '' OR name LIKE $2::text || '%')
+  AND ($3::boolean IS NULL OR (coalesce(bio, '') <> '') = $3::boolean)
+ORDER BY ts_rank(to_tsvector('english', coalesce(bio, '')), websearch_to_tsquery('english', $1::text)) DESC,
+         name, id
+LIMIT $4
+`
+
+type SearchAuthorsParams struct {
+	Query       string
+	NamePrefix  string
+	HasBio      pgtype.Bool
+	ResultLimit int32
+}
+
+func (q *Queries) SearchAuthors(ctx context.Context, arg SearchAuthorsParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, searchAuthors,
+		arg.Query,
+		arg.NamePrefix,
+		arg.HasBio,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAuthor = `-- name: UpdateAuthor :execrows
+UPDATE authors
+  set name = $1,
+  bio = $2,
+  version = version + 1
+WHERE id = $3 AND deleted_at IS NULL
+  AND ($4::integer = 0 OR version = $4::integer)
+`
+
+type UpdateAuthorParams struct {
+	Name            string
+	Bio             pgtype.Text
+	ID              int64
+	ExpectedVersion int32
+}
+
+// An expected_version of 0 skips the optimistic concurrency check.
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAuthor,
+		arg.Name,
+		arg.Bio,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateBook = `-- name: UpdateBook :execrows
+UPDATE books
+  set author_id = $2,
+  title = $3,
+  isbn = $4,
+  published_at = $5
+WHERE id = $1
+`
+
+type UpdateBookParams struct {
+	ID          int64
+	AuthorID    int64
+	Title       string
+	Isbn        pgtype.Text
+	PublishedAt pgtype.Date
+}
+
+func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateBook,
+		arg.ID,
+		arg.AuthorID,
+		arg.Title,
+		arg.Isbn,
+		arg.PublishedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertAuthor = `-- name: UpsertAuthor :one
+INSERT INTO authors (
+  external_id, name, bio
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (tenant_id, external_id) DO UPDATE
+  set name = EXCLUDED.name,
+  bio = EXCLUDED.bio,
+  deleted_at = NULL,
+  version = authors.version + 1
+RETURNING id, name, bio, deleted_at, version, tenant_id, external_id, (xmax = 0) AS inserted
+`
+
+type UpsertAuthorParams struct {
+	ExternalID pgtype.Text
+	Name       string
+	Bio        pgtype.Text
+}
+
+type UpsertAuthorRow struct {
+	ID         int64
+	Name       string
+	Bio        pgtype.Text
+	DeletedAt  pgtype.Timestamptz
+	Version    int32
+	TenantID   string
+	ExternalID pgtype.Text
+	Inserted   bool
+}
+
+// Creates the author with the given external_id or updates it, bringing it
+// back if soft-deleted. inserted reports which of the two happened.
+func (q *Queries) UpsertAuthor(ctx context.Context, arg UpsertAuthorParams) (UpsertAuthorRow, error) {
+	row := q.db.QueryRow(ctx, upsertAuthor, arg.ExternalID, arg.Name, arg.Bio)
+	var i UpsertAuthorRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+		&i.ExternalID,
+		&i.Inserted,
+	)
+	return i, err
+}